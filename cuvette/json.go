@@ -0,0 +1,255 @@
+package cuvette
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiPage is one page of Cuvette's job search API, as in xyz.json.
+type apiPage struct {
+	Success    bool     `json:"success"`
+	Data       []apiJob `json:"data"`
+	PageSize   int      `json:"pageSize"`
+	PageNumber int      `json:"pageNumber"`
+	Total      int      `json:"total"`
+}
+
+type apiJob struct {
+	ID                   string        `json:"_id"`
+	RefSkills            []apiRef      `json:"refSkills"`
+	RefOptionalSkills    []apiRef      `json:"refOptionalSkills"`
+	JobOffer             []float64     `json:"jobOffer"`             // CTC range in LPA
+	ProbationSalaryRange []float64     `json:"probationSalaryRange"` // monthly, in rupees
+	ProbationDuration    string        `json:"probationDuration"`    // months
+	RefUser              apiUser       `json:"refUser"`
+	StartDate            string        `json:"startDate"`
+	NumOfOpenings        int           `json:"numOfOpenings"`
+	RefJobTitle          apiRef        `json:"refJobTitle"`
+	IsRemoteJob          bool          `json:"isRemoteJob"`
+	RefLocation          *apiLocation  `json:"refLocation"`
+	YearsOfExperience    int           `json:"yearsOfExperience"`
+	CreatedAt            time.Time     `json:"createdAt"`
+	ApplicantCount       int           `json:"applicantCount"`
+	Preferences          apiPreference `json:"preferences"`
+}
+
+type apiRef struct {
+	ID   string `json:"_id"`
+	Name string `json:"name"`
+}
+
+type apiUser struct {
+	RefCompanyProfile struct {
+		CompanyName string       `json:"companyName"`
+		LogoURL     string       `json:"logoUrl"`
+		RefLocation *apiLocation `json:"refLocation"`
+	} `json:"refCompanyProfile"`
+}
+
+type apiLocation struct {
+	City    string `json:"city"`
+	State   string `json:"state"`
+	Country string `json:"country"`
+}
+
+type apiPreference struct {
+	ProbationPeriod bool `json:"probationPeriod"`
+}
+
+// ParseJobsJSON reads one or more pages of Cuvette's job API from reader and
+// returns their listings in the same shape as ScrapeCuvetteListings. Pages
+// may be concatenated one after another or wrapped in a JSON array; a
+// listing repeated across pages is returned once.
+func ParseJobsJSON(reader io.Reader) ([]JobDetail, error) {
+	dec := json.NewDecoder(reader)
+
+	var listings []JobDetail
+	seen := make(map[string]bool)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("could not parse JSON: %w", err)
+		}
+
+		var pages []apiPage
+		if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+			if err := json.Unmarshal(trimmed, &pages); err != nil {
+				return nil, fmt.Errorf("could not parse JSON pages: %w", err)
+			}
+		} else {
+			var page apiPage
+			if err := json.Unmarshal(trimmed, &page); err != nil {
+				return nil, fmt.Errorf("could not parse JSON page: %w", err)
+			}
+			pages = append(pages, page)
+		}
+
+		for _, page := range pages {
+			for _, job := range page.Data {
+				if job.ID != "" && seen[job.ID] {
+					continue
+				}
+				seen[job.ID] = true
+				listings = append(listings, job.detail())
+			}
+		}
+	}
+
+	return listings, nil
+}
+
+// IsJSON reports whether the payload starting in r looks like JSON rather
+// than HTML.
+func IsJSON(r *bufio.Reader) bool {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return false
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			r.ReadByte()
+		case '{', '[':
+			return true
+		default:
+			return false
+		}
+	}
+}
+
+func (j apiJob) detail() JobDetail {
+	company := j.RefUser.RefCompanyProfile
+
+	typ := "internship"
+	if len(j.JobOffer) > 0 {
+		typ = "job"
+	}
+
+	mode := "In office"
+	if j.IsRemoteJob {
+		mode = "Work from home"
+	}
+
+	location := formatLocation(j.RefLocation)
+	officeLocation := formatLocation(company.RefLocation)
+	if location == "" {
+		if j.IsRemoteJob {
+			location = "Remote"
+		} else {
+			location = officeLocation
+		}
+	}
+
+	var skills []string
+	for _, s := range j.RefSkills {
+		if name := strings.TrimSpace(s.Name); name != "" {
+			skills = append(skills, name)
+		}
+	}
+	var optional []string
+	for _, s := range j.RefOptionalSkills {
+		if name := strings.TrimSpace(s.Name); name != "" {
+			optional = append(optional, name)
+		}
+	}
+
+	var startDate string
+	if t, err := time.Parse(time.RFC3339, j.StartDate); err == nil {
+		startDate = t.Format("2 Jan 2006")
+	}
+
+	var probationDuration string
+	if j.ProbationDuration != "" {
+		probationDuration = j.ProbationDuration + " months"
+	}
+
+	detail := JobDetail{
+		Role:              strings.TrimSpace(j.RefJobTitle.Name),
+		CompanyName:       strings.TrimSpace(company.CompanyName),
+		CompanyPhotoURL:   company.LogoURL,
+		Location:          location,
+		Salary:            formatRange(j.JobOffer, "₹", " LPA"),
+		Mode:              mode,
+		StartDate:         startDate,
+		OfficeLocation:    officeLocation,
+		Type:              typ,
+		ID:                j.ID,
+		ApplyURL:          "https://cuvette.tech/" + typ + "/" + j.ID,
+		Skills:            skills,
+		OptionalSkills:    optional,
+		Experience:        experience(j.YearsOfExperience),
+		Openings:          j.NumOfOpenings,
+		Applicants:        j.ApplicantCount,
+		ProbationStipend:  formatRange(j.ProbationSalaryRange, "₹", " /month"),
+		ProbationDuration: probationDuration,
+	}
+	if !j.CreatedAt.IsZero() {
+		createdAt := j.CreatedAt
+		detail.PostedAt = &createdAt
+	}
+	return detail
+}
+
+func formatLocation(loc *apiLocation) string {
+	if loc == nil {
+		return ""
+	}
+	var parts []string
+	for _, p := range []string{loc.City, loc.State, loc.Country} {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatRange renders [min, max] as e.g. "₹4 - 5 LPA" or "₹40,000 - 50,000 /month".
+func formatRange(values []float64, prefix, suffix string) string {
+	switch {
+	case len(values) == 0:
+		return ""
+	case len(values) == 1 || values[0] == values[1]:
+		return prefix + formatAmount(values[0]) + suffix
+	default:
+		return prefix + formatAmount(values[0]) + " - " + formatAmount(values[1]) + suffix
+	}
+}
+
+// formatAmount groups thousands the Indian way (8,00,000) and keeps
+// fractions such as 3.6.
+func formatAmount(v float64) string {
+	if v != float64(int64(v)) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	digits := strconv.FormatInt(int64(v), 10)
+	if len(digits) <= 3 {
+		return digits
+	}
+	head, tail := digits[:len(digits)-3], digits[len(digits)-3:]
+	var groups []string
+	for len(head) > 2 {
+		groups = append([]string{head[len(head)-2:]}, groups...)
+		head = head[:len(head)-2]
+	}
+	groups = append([]string{head}, groups...)
+	return strings.Join(groups, ",") + "," + tail
+}
+
+func experience(years int) string {
+	switch years {
+	case 0:
+		return "Fresher"
+	case 1:
+		return "1 year"
+	default:
+		return strconv.Itoa(years) + " years"
+	}
+}
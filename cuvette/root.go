@@ -6,6 +6,9 @@ import (
 	"log"
 	"net/url"
 	"strings"
+	"time"
+
+	"aiapply/scraper"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -28,6 +31,15 @@ type JobDetail struct {
 	ApplyURL        string
 	Skills          []string
 	Level           string
+
+	// Only filled from the JSON API
+	OptionalSkills    []string
+	Experience        string
+	Openings          int
+	Applicants        int
+	ProbationStipend  string
+	ProbationDuration string
+	PostedAt          *time.Time
 }
 
 // ScrapeCuvetteListings reads HTML from reader and returns a slice of JobDetail.
@@ -37,16 +49,16 @@ func ScrapeCuvetteListings(reader io.Reader) ([]JobDetail, error) {
 		return nil, fmt.Errorf("could not parse HTML: %w", err)
 	}
 
+	sel := scraper.SelectorsFor("cuvette")
+
 	var listings []JobDetail
 
-	doc.Find("div[class^='StudentInternshipCard_container']").Each(func(_ int, s *goquery.Selection) {
+	sel.Find(doc.Selection, "card").Each(func(_ int, s *goquery.Selection) {
 		// Basic header
-		role := strings.TrimSpace(s.Find("h3").Text())
+		role := sel.Text(s, "role")
 
 		// Company | Location
-		rawHeader := strings.TrimSpace(
-			s.Find("div[class^='StudentInternshipCard_heading'] > p").Text(),
-		)
+		rawHeader := sel.Text(s, "heading")
 		parts := strings.Split(rawHeader, "|")
 		companyName := strings.TrimSpace(parts[0])
 		location := ""
@@ -55,23 +67,17 @@ func ScrapeCuvetteListings(reader io.Reader) ([]JobDetail, error) {
 		}
 
 		// Logo
-		companyPhotoURL, _ := s.Find("img").Attr("src")
+		companyPhotoURL := sel.Attr(s, "logo", "src")
 
 		// Salary (first infoValue)
-		salary := strings.TrimSpace(
-			s.Find("div[class^='StudentInternshipCard_infoValue']").First().Text(),
-		)
+		salary := sel.Text(s, "info_value")
 
 		// Duration / Mode / Start Date / Office Location
 		var duration, mode, startDate, officeLocation string
-		s.Find("div[class^='StudentInternshipCard_info']").Each(
+		sel.Find(s, "info").Each(
 			func(_ int, info *goquery.Selection) {
-				label := strings.TrimSpace(
-					info.Find("div[class^='StudentInternshipCard_infoTop']").Text(),
-				)
-				value := strings.TrimSpace(
-					info.Find("div[class^='StudentInternshipCard_infoValue']").Text(),
-				)
+				label := strings.TrimSpace(sel.Find(info, "info_label").Text())
+				value := strings.TrimSpace(sel.Find(info, "info_value").Text())
 				switch label {
 				case "Duration":
 					duration = value
@@ -87,7 +93,7 @@ func ScrapeCuvetteListings(reader io.Reader) ([]JobDetail, error) {
 
 		// ApplyBy & PostedAgo
 		var applyBy, postedAgo string
-		if p := s.Find("div[class^='StudentInternshipCard_currentInfoLeft'] p").First(); p.Length() > 0 {
+		if p := sel.Find(s, "dates").First(); p.Length() > 0 {
 			line := strings.TrimSpace(p.Text())
 			parts := strings.Split(line, "•")
 			if len(parts) > 0 {
@@ -100,7 +106,7 @@ func ScrapeCuvetteListings(reader io.Reader) ([]JobDetail, error) {
 
 		// Try to pull out the <a href> around “View Details” or “Apply Now”
 		var applyURL, typ, id string
-		if linkSel := s.Find("p[class^='StudentInternshipCard_outline']").Closest("a"); linkSel.Length() > 0 {
+		if linkSel := sel.Find(s, "apply_link").Closest("a"); linkSel.Length() > 0 {
 			if href, ok := linkSel.Attr("href"); ok {
 				applyURL = href
 				log.Printf("Found apply URL: %s", applyURL)
@@ -116,14 +122,12 @@ func ScrapeCuvetteListings(reader io.Reader) ([]JobDetail, error) {
 
 		// Skills
 		var skills []string
-		s.Find("div[class^='StudentInternshipCard_skill']").Each(func(_ int, skill *goquery.Selection) {
+		sel.Find(s, "skill").Each(func(_ int, skill *goquery.Selection) {
 			skills = append(skills, strings.TrimSpace(skill.Text()))
 		})
 
 		// Level badge
-		level := strings.TrimSpace(
-			s.Find("p[class^='sc-iUuxjF']").First().Text(),
-		)
+		level := sel.Text(s, "level")
 
 		listings = append(listings, JobDetail{
 			Role:            role,
@@ -0,0 +1,107 @@
+package cuvette
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+
+	"aiapply/scraper"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func init() {
+	scraper.Register(Scraper{})
+}
+
+// Scraper maps Cuvette listings into scraper jobs.
+type Scraper struct{}
+
+func (Scraper) Platform() string { return "cuvette" }
+
+func (Scraper) Fingerprint() scraper.Fingerprint {
+	return scraper.Fingerprint{
+		Hosts:     []string{"cuvette.tech"},
+		SiteNames: []string{"Cuvette"},
+		Markers:   []string{"card"},
+		Strings:   []string{`"refJobTitle"`, `"refCompanyProfile"`, "cuvette.tech"},
+	}
+}
+
+// FetchHints scrolls; Cuvette loads more cards as the page nears its end.
+func (Scraper) FetchHints() scraper.FetchHints {
+	return scraper.FetchHints{Card: scraper.SelectorsFor("cuvette").Group("card")}
+}
+
+// CountCards counts listing cards, or the listings of an API response.
+func (Scraper) CountCards(r io.Reader) (int, error) {
+	br := bufio.NewReader(r)
+	if IsJSON(br) {
+		listings, err := ParseJobsJSON(br)
+		return len(listings), err
+	}
+	doc, err := goquery.NewDocumentFromReader(br)
+	if err != nil {
+		return 0, err
+	}
+	return scraper.SelectorsFor("cuvette").Find(doc.Selection, "card").Length(), nil
+}
+
+// Scrape accepts both a saved listings page and a response of Cuvette's job API.
+func (Scraper) Scrape(r io.Reader) ([]scraper.Job, error) {
+	br := bufio.NewReader(r)
+	var listings []JobDetail
+	var err error
+	if IsJSON(br) {
+		listings, err = ParseJobsJSON(br)
+	} else {
+		listings, err = ScrapeCuvetteListings(br)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]scraper.Job, 0, len(listings))
+	for _, l := range listings {
+		job := scraper.Job{
+			ID:       l.ID,
+			Platform: "cuvette",
+			Role:     l.Role,
+			Company:  l.CompanyName,
+			Location: l.Location,
+			Salary:   l.Salary,
+			URL:      absoluteURL(l.ApplyURL),
+			Skills:   l.Skills,
+			PostedAt: l.PostedAt,
+		}
+		job.SetExtra("company_photo_url", l.CompanyPhotoURL)
+		job.SetExtra("duration", l.Duration)
+		job.SetExtra("mode", l.Mode)
+		job.SetExtra("start_date", l.StartDate)
+		job.SetExtra("office_location", l.OfficeLocation)
+		job.SetExtra("apply_by", l.ApplyBy)
+		job.SetExtra("posted_ago", l.PostedAgo)
+		job.SetExtra("type", l.Type)
+		job.SetExtra("level", l.Level)
+		job.SetExtra("optional_skills", strings.Join(l.OptionalSkills, ", "))
+		job.SetExtra("experience", l.Experience)
+		job.SetExtra("probation_stipend", l.ProbationStipend)
+		job.SetExtra("probation_duration", l.ProbationDuration)
+		if l.Openings > 0 {
+			job.SetExtra("openings", strconv.Itoa(l.Openings))
+		}
+		if l.Applicants > 0 {
+			job.SetExtra("applicants", strconv.Itoa(l.Applicants))
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func absoluteURL(href string) string {
+	if strings.HasPrefix(href, "/") {
+		return "https://cuvette.tech" + href
+	}
+	return href
+}
@@ -70,4 +70,23 @@ func UpdateAnalytics(db *gorm.DB, userID int, platform string, isColdEmail bool)
 
 		return nil
 	})
-}
\ No newline at end of file
+}
+
+// GetEngagementStats counts sent, opened, clicked and replied cold emails of a user
+func GetEngagementStats(db *gorm.DB, userID int) (*models.EngagementStats, error) {
+	var stats models.EngagementStats
+	err := db.Model(&models.EmailJob{}).
+		Select("COUNT(*) AS sent, COUNT(opened_at) AS opened, COUNT(clicked_at) AS clicked, COUNT(replied_at) AS replied").
+		Where("user_id = ? AND status = ?", userID, models.EmailJobSent).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if stats.Sent > 0 {
+		stats.OpenRate = float64(stats.Opened) / float64(stats.Sent)
+		stats.ClickRate = float64(stats.Clicked) / float64(stats.Sent)
+		stats.ReplyRate = float64(stats.Replied) / float64(stats.Sent)
+	}
+	return &stats, nil
+}
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"aiapply/models"
+
+	"gorm.io/gorm"
+)
+
+// coldEmailStatusRank orders ColdEmail statuses so they only ever move forward
+var coldEmailStatusRank = map[string]int{
+	"queued":  0,
+	"sent":    1,
+	"opened":  2,
+	"clicked": 3,
+	"replied": 4,
+}
+
+// AdvanceColdEmailStatus moves the cold email of an application to status
+// unless it has already progressed further
+func AdvanceColdEmailStatus(db *gorm.DB, applicationID uint, status string) error {
+	rank, ok := coldEmailStatusRank[status]
+	if !ok {
+		return nil
+	}
+
+	var behind []string
+	for s, r := range coldEmailStatusRank {
+		if r < rank {
+			behind = append(behind, s)
+		}
+	}
+	return db.Model(&models.ColdEmail{}).
+		Where("application_id = ? AND (status IN ? OR status = '')", applicationID, behind).
+		Update("status", status).Error
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"strings"
+
+	"aiapply/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecordDomainPattern remembers that pattern produced a working address at
+// domain, either because it was verified or because it got a reply
+func RecordDomainPattern(db *gorm.DB, domain, pattern string, replied bool) error {
+	if domain == "" || pattern == "" {
+		return nil
+	}
+	row := models.DomainPattern{Domain: strings.ToLower(domain), Pattern: pattern}
+	column := "verified"
+	if replied {
+		row.Replied = 1
+		column = "replied"
+	} else {
+		row.Verified = 1
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "domain"}, {Name: "pattern"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			column:       gorm.Expr("domain_patterns." + column + " + 1"),
+			"updated_at": gorm.Expr("NOW()"),
+		}),
+	}).Create(&row).Error
+}
+
+// GetDomainPatterns returns the patterns learned for a domain, best first.
+// Replies count for more than verifications
+func GetDomainPatterns(db *gorm.DB, domain string) ([]string, error) {
+	var patterns []string
+	err := db.Model(&models.DomainPattern{}).
+		Where("domain = ?", strings.ToLower(domain)).
+		Order("replied DESC, verified DESC, updated_at DESC").
+		Pluck("pattern", &patterns).Error
+	return patterns, err
+}
+
+// GetPatternSuccessRates returns, per pattern, the share of checked candidate
+// addresses across all domains that verified as valid. Rates are smoothed
+// towards 0.5 so a pattern tried only a few times is not ranked on luck
+func GetPatternSuccessRates(db *gorm.DB) (map[string]float64, error) {
+	var rows []struct {
+		Pattern string
+		Checked int64
+		Valid   int64
+	}
+	err := db.Model(&models.EmailCandidate{}).
+		Select("pattern, COUNT(*) AS checked, SUM(CASE WHEN verification = 'valid' THEN 1 ELSE 0 END) AS valid").
+		Where("verification IN ?", []string{"valid", "invalid"}).
+		Group("pattern").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make(map[string]float64, len(rows))
+	for _, r := range rows {
+		rates[r.Pattern] = float64(r.Valid+1) / float64(r.Checked+2)
+	}
+	return rates, nil
+}
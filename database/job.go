@@ -0,0 +1,189 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"aiapply/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JobFilter narrows down ListJobs. Empty fields match everything
+type JobFilter struct {
+	Platform string
+	Company  string
+	Location string
+	Skill    string
+	Since    *time.Time
+	Until    *time.Time
+	// Open leaves out jobs whose application deadline has passed
+	Open bool
+
+	// City matches any listed city or metro; RemoteOrCity also accepts
+	// remote jobs anywhere
+	City         string
+	Country      string
+	RemotePolicy string
+	RemoteOrCity bool
+
+	// Pay filters compare amounts within one currency and period
+	Currency  string
+	Period    string
+	MinSalary float64
+	// SortBySalary orders by the top of the salary range instead of date
+	SortBySalary bool
+
+	Limit  int
+	Offset int
+}
+
+// UpsertJobs stores scraped jobs, refreshing the ones already known by
+// platform and external ID. FirstSeenAt is only set on insert. A scrape that
+// lacks a field keeps the stored value, and extras are merged, so a sparser
+// page such as a search result does not wipe what a detail page gave
+func UpsertJobs(db *gorm.DB, jobs []models.Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	// Postgres refuses to update the same row twice in one statement
+	now := time.Now()
+	seen := make(map[[2]string]int, len(jobs))
+	unique := make([]models.Job, 0, len(jobs))
+	for _, job := range jobs {
+		job.FirstSeenAt = now
+		job.LastSeenAt = now
+		key := [2]string{job.Platform, job.ExternalID}
+		if i, ok := seen[key]; ok {
+			unique[i] = job
+			continue
+		}
+		seen[key] = len(unique)
+		unique = append(unique, job)
+	}
+
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "platform"}, {Name: "external_id"}},
+		DoUpdates: jobUpdates(),
+	}).CreateInBatches(&unique, 200).Error
+}
+
+// jobUpdates is how a stored job takes in a new scrape of it
+func jobUpdates() clause.Set {
+	set := clause.AssignmentColumns([]string{"last_seen_at", "updated_at"})
+	assign := func(column, expr string) {
+		set = append(set, clause.Assignment{Column: clause.Column{Name: column}, Value: gorm.Expr(expr)})
+	}
+
+	for _, column := range []string{"role", "company", "location", "salary", "url"} {
+		assign(column, fmt.Sprintf("COALESCE(NULLIF(EXCLUDED.%s, ''), jobs.%[1]s)", column))
+	}
+	for _, column := range []string{"posted_at", "apply_by"} {
+		assign(column, fmt.Sprintf("COALESCE(EXCLUDED.%s, jobs.%[1]s)", column))
+	}
+	assign("skills", "COALESCE(NULLIF(EXCLUDED.skills, '[]'::jsonb), jobs.skills)")
+	assign("extras", "COALESCE(jobs.extras, '{}'::jsonb) || COALESCE(EXCLUDED.extras, '{}'::jsonb)")
+
+	// parsed from salary and location, so they change only along with them
+	derived := map[string][]string{
+		"salary":   {"salary_min", "salary_max", "salary_currency", "salary_period", "equity_min", "equity_max", "stipend"},
+		"location": {"city", "region", "country", "remote_policy", "cities"},
+	}
+	for _, source := range []string{"salary", "location"} {
+		for _, column := range derived[source] {
+			assign(column, fmt.Sprintf("CASE WHEN EXCLUDED.%s = '' THEN jobs.%s ELSE EXCLUDED.%[2]s END", source, column))
+		}
+	}
+	return set
+}
+
+// ListJobs returns stored jobs matching filter, newest first
+func ListJobs(db *gorm.DB, filter JobFilter) ([]models.Job, error) {
+	query := db.Model(&models.Job{})
+	if filter.Platform != "" {
+		query = query.Where("platform = ?", filter.Platform)
+	}
+	if filter.Company != "" {
+		query = query.Where(`company ILIKE ? ESCAPE '\'`, "%"+escapeLike(filter.Company)+"%")
+	}
+	if filter.Location != "" {
+		query = query.Where(`location ILIKE ? ESCAPE '\'`, "%"+escapeLike(filter.Location)+"%")
+	}
+	if filter.Skill != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE WHEN jsonb_typeof(skills) = 'array' THEN skills ELSE '[]'::jsonb END) AS skill WHERE skill ILIKE ? ESCAPE '\\')", escapeLike(filter.Skill))
+	}
+	if filter.Since != nil {
+		query = query.Where("COALESCE(posted_at, first_seen_at) >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("COALESCE(posted_at, first_seen_at) < ?", *filter.Until)
+	}
+	if filter.Open {
+		query = query.Where("apply_by IS NULL OR apply_by >= ?", time.Now())
+	}
+
+	if filter.Country != "" {
+		query = query.Where("country = ?", filter.Country)
+	}
+	if filter.RemotePolicy != "" {
+		query = query.Where("remote_policy = ?", filter.RemotePolicy)
+	}
+	if filter.City != "" {
+		inCity := "EXISTS (SELECT 1 FROM jsonb_array_elements_text(CASE WHEN jsonb_typeof(cities) = 'array' THEN cities ELSE '[]'::jsonb END) AS city WHERE city ILIKE ? ESCAPE '\\')"
+		if filter.RemoteOrCity {
+			query = query.Where("(remote_policy = 'remote' OR "+inCity+")", escapeLike(filter.City))
+		} else {
+			query = query.Where(inCity, escapeLike(filter.City))
+		}
+	} else if filter.RemoteOrCity {
+		query = query.Where("remote_policy = 'remote'")
+	}
+	if filter.Currency != "" {
+		query = query.Where("salary_currency = ?", filter.Currency)
+	}
+	if filter.Period != "" {
+		query = query.Where("salary_period = ?", filter.Period)
+	}
+	if filter.MinSalary > 0 {
+		query = query.Where("salary_max >= ?", filter.MinSalary)
+	}
+
+	order := "COALESCE(posted_at, first_seen_at) DESC, id DESC"
+	if filter.SortBySalary {
+		order = "salary_max DESC NULLS LAST, " + order
+	}
+
+	var jobs []models.Job
+	err := query.Order(order).
+		Limit(filter.Limit).Offset(filter.Offset).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+// likeEscaper quotes the wildcards of a LIKE pattern, for use with ESCAPE '\'
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike makes user input match itself literally in a LIKE pattern
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// CompanyPage is a company with jobs on a platform and the company page its
+// latest job links to
+type CompanyPage struct {
+	Company string
+	URL     string
+}
+
+// PlatformCompanies returns every company with jobs on platform
+func PlatformCompanies(db *gorm.DB, platform string) ([]CompanyPage, error) {
+	var companies []CompanyPage
+	err := db.Raw(`SELECT DISTINCT ON (company) company, COALESCE(extras->>'company_url', '') AS url
+		FROM jobs WHERE platform = ? AND company <> ''
+		ORDER BY company, last_seen_at DESC`, platform).
+		Scan(&companies).Error
+	return companies, err
+}
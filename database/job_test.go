@@ -0,0 +1,100 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"aiapply/database/dbtest"
+	"aiapply/models"
+)
+
+func TestUpsertJobsKeepsStoredFields(t *testing.T) {
+	db := dbtest.Open(t, &models.Job{})
+
+	posted := time.Date(2026, time.January, 2, 0, 0, 0, 0, time.UTC)
+	salaryMax := 1200000.0
+	full := models.Job{
+		Platform: "linkedin", ExternalID: "42", Role: "Backend Engineer", Company: "Acme",
+		Location: "Bengaluru, Karnataka, India", Salary: "₹10L – ₹12L", SalaryMax: &salaryMax, SalaryCurrency: "INR",
+		City: "Bengaluru", Country: "IN", Cities: []string{"Bengaluru"},
+		URL: "https://www.linkedin.com/jobs/view/42/", Skills: []string{"Go"}, PostedAt: &posted,
+		Extras: map[string]string{"description": "Build APIs", "seniority": "Mid-Senior level"},
+	}
+	if err := UpsertJobs(db, []models.Job{full}); err != nil {
+		t.Fatal(err)
+	}
+
+	// a sparser scrape of the same job
+	sparse := models.Job{
+		Platform: "linkedin", ExternalID: "42", Role: "Backend Engineer II", Company: "Acme",
+		Extras: map[string]string{"applicants": "53"},
+	}
+	if err := UpsertJobs(db, []models.Job{sparse}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got models.Job
+	if err := db.Where("platform = ? AND external_id = ?", "linkedin", "42").First(&got).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.Role != "Backend Engineer II" {
+		t.Errorf("role = %q, want the newer one", got.Role)
+	}
+	if got.Salary != full.Salary || got.SalaryMax == nil || *got.SalaryMax != salaryMax || got.SalaryCurrency != "INR" {
+		t.Errorf("salary = %q %v %q, want it kept", got.Salary, got.SalaryMax, got.SalaryCurrency)
+	}
+	if got.Location != full.Location || got.City != "Bengaluru" || len(got.Cities) != 1 {
+		t.Errorf("location = %q %q %v, want it kept", got.Location, got.City, got.Cities)
+	}
+	if got.URL != full.URL || len(got.Skills) != 1 || got.PostedAt == nil || !got.PostedAt.Equal(posted) {
+		t.Errorf("url, skills or posted_at lost: %q %v %v", got.URL, got.Skills, got.PostedAt)
+	}
+	if got.Extras["description"] != "Build APIs" || got.Extras["seniority"] != "Mid-Senior level" || got.Extras["applicants"] != "53" {
+		t.Errorf("extras = %v, want both scrapes merged", got.Extras)
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := map[string]string{
+		"Acme":      "Acme",
+		"100%":      `100\%`,
+		"ac_me":     `ac\_me`,
+		`back\side`: `back\\side`,
+	}
+	for in, want := range tests {
+		if got := escapeLike(in); got != want {
+			t.Errorf("escapeLike(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestListJobsMatchesWildcardsLiterally(t *testing.T) {
+	db := dbtest.Open(t, &models.Job{})
+	for i, company := range []string{"Acme", "100% Remote Co", "ab_cd"} {
+		job := models.Job{Platform: "naukri", ExternalID: string(rune('a' + i)), Company: company, Location: "Pune"}
+		if err := db.Create(&job).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		filter JobFilter
+		want   int
+	}{
+		{JobFilter{Company: "%"}, 1},
+		{JobFilter{Company: "_"}, 1},
+		{JobFilter{Company: "acme"}, 1},
+		{JobFilter{Location: "%"}, 0},
+		{JobFilter{Location: "pu"}, 3},
+	}
+	for _, tt := range tests {
+		tt.filter.Limit = 10
+		jobs, err := ListJobs(db, tt.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(jobs) != tt.want {
+			t.Errorf("ListJobs(%+v) found %d jobs, want %d", tt.filter, len(jobs), tt.want)
+		}
+	}
+}
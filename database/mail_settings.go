@@ -0,0 +1,28 @@
+package database
+
+import (
+	"aiapply/models"
+
+	"gorm.io/gorm"
+)
+
+// GetMailSettings returns the outgoing mail configuration of a user
+func GetMailSettings(db *gorm.DB, userID uint) (*models.MailSettings, error) {
+	var settings models.MailSettings
+	if err := db.Where("user_id = ?", userID).First(&settings).Error; err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// SaveMailSettings creates or replaces the outgoing mail configuration of a user
+func SaveMailSettings(db *gorm.DB, settings *models.MailSettings) error {
+	var existing models.MailSettings
+	err := db.Where("user_id = ?", settings.UserID).First(&existing).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	settings.ID = existing.ID
+	settings.CreatedAt = existing.CreatedAt
+	return db.Save(settings).Error
+}
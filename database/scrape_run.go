@@ -0,0 +1,36 @@
+package database
+
+import (
+	"aiapply/models"
+
+	"gorm.io/gorm"
+)
+
+// RecordScrapeRun stores the diagnostics of one scrape
+func RecordScrapeRun(db *gorm.DB, run *models.ScrapeRun) error {
+	return db.Create(run).Error
+}
+
+// LastScrapeRun returns the latest run of platform that produced jobs, or
+// nil when there is none
+func LastScrapeRun(db *gorm.DB, platform string) (*models.ScrapeRun, error) {
+	var runs []models.ScrapeRun
+	err := db.Where("platform = ? AND jobs > 0", platform).
+		Order("created_at DESC").Limit(1).
+		Find(&runs).Error
+	if err != nil || len(runs) == 0 {
+		return nil, err
+	}
+	return &runs[0], nil
+}
+
+// ListScrapeRuns returns the latest runs, of one platform when given
+func ListScrapeRuns(db *gorm.DB, platform string, limit int) ([]models.ScrapeRun, error) {
+	query := db.Model(&models.ScrapeRun{})
+	if platform != "" {
+		query = query.Where("platform = ?", platform)
+	}
+	var runs []models.ScrapeRun
+	err := query.Order("created_at DESC").Limit(limit).Find(&runs).Error
+	return runs, err
+}
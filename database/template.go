@@ -0,0 +1,16 @@
+package database
+
+import (
+	"aiapply/models"
+
+	"gorm.io/gorm"
+)
+
+// GetTemplate returns one of a user's email templates
+func GetTemplate(db *gorm.DB, userID, templateID uint) (*models.EmailTemplate, error) {
+	var tmpl models.EmailTemplate
+	if err := db.Where("id = ? AND user_id = ?", templateID, userID).First(&tmpl).Error; err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
@@ -1,11 +1,14 @@
 package emailer
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net"
 	"net/mail"
 	"net/smtp"
+	"net/textproto"
 	"strings"
 	"time"
 )
@@ -17,17 +20,62 @@ var disposableDomains = map[string]struct{}{
 	"10minutemail.com": {},
 }
 
+// Verification outcomes reported by VerifyEmail.
+const (
+	VerificationValid   = "valid"
+	VerificationInvalid = "invalid" // the address or its domain was rejected
+	VerificationError   = "error"   // the check could not be completed
+	VerificationRisky   = "risky"   // unknown: the domain accepts mail for any address
+)
+
+// VerificationResult describes how an address fared in VerifyEmail.
+type VerificationResult struct {
+	Address  string `json:"address"`
+	Result   string `json:"result"` // valid, invalid, error
+	Reason   string `json:"reason,omitempty"`
+	MXHost   string `json:"mx_host,omitempty"`
+	SMTPCode int    `json:"smtp_code,omitempty"` // reply code of the RCPT TO, or of the failing command
+	CatchAll bool   `json:"catch_all"`
+
+	// probed is set when this check learned whether the domain is catch-all
+	probed bool
+}
+
+// Err converts a failed result into an error, nil when the address is valid.
+func (r VerificationResult) Err() error {
+	if r.Result == VerificationValid {
+		return nil
+	}
+	return errors.New(r.Reason)
+}
+
 // ValidateEmail performs a multi-step validation:
 // 1. Syntax check
 // 2. Domain validity
 // 3. Disposable domain check
 // 4. MX record lookup
-// 5. SMTP mailbox check
+// 5. Catch-all check
+// 6. SMTP mailbox check
 func ValidateEmail(address string) error {
+	return VerifyEmail(address).Err()
+}
+
+// VerifyEmail runs the ValidateEmail checks and reports the detailed outcome.
+func VerifyEmail(address string) VerificationResult {
+	return verifyEmail(address, lookupMXHosts, nil)
+}
+
+// verifyEmail is VerifyEmail with the MX lookup supplied by the caller, so a
+// cache can answer it. catchAll is the known catch-all status of the domain,
+// nil when it has to be probed.
+func verifyEmail(address string, lookupMX func(domain string) ([]string, error), catchAll *bool) VerificationResult {
+	res := VerificationResult{Address: address, Result: VerificationInvalid}
+
 	// 1. Syntax check
 	email, err := mail.ParseAddress(address)
 	if err != nil {
-		return errors.New("invalid email format")
+		res.Reason = "invalid email format"
+		return res
 	}
 
 	parts := strings.Split(email.Address, "@")
@@ -35,54 +83,148 @@ func ValidateEmail(address string) error {
 
 	// 2. Domain validity (basic)
 	if len(domain) == 0 || !strings.Contains(domain, ".") {
-		return errors.New("invalid domain")
+		res.Reason = "invalid domain"
+		return res
 	}
 
 	// 3. Disposable domain check
 	if _, found := disposableDomains[strings.ToLower(domain)]; found {
-		return errors.New("disposable email addresses are not allowed")
+		res.Reason = "disposable email addresses are not allowed"
+		return res
 	}
 
-	// 4. MX record lookup
-	mxRecords, err := net.LookupMX(domain)
-	if err != nil || len(mxRecords) == 0 {
-		return fmt.Errorf("no MX records found for domain %s", domain)
+	// 4. MX record lookup; only a domain known not to exist is rejected, a
+	// timeout or SERVFAIL says nothing about the address
+	mxHosts, err := lookupMX(domain)
+	var dnsErr *net.DNSError
+	if err != nil && !(errors.As(err, &dnsErr) && dnsErr.IsNotFound) {
+		res.Result = VerificationError
+		res.Reason = fmt.Sprintf("MX lookup for domain %s failed: %v", domain, err)
+		return res
+	}
+	if len(mxHosts) == 0 {
+		res.Reason = fmt.Sprintf("no MX records found for domain %s", domain)
+		return res
 	}
 
 	// pick the first MX host
-	mxHost := mxRecords[0].Host
+	res.MXHost = mxHosts[0]
+
+	// 5. Catch-all check: a known catch-all domain is not worth a handshake
+	if catchAll != nil && *catchAll {
+		res.Result = VerificationRisky
+		res.Reason = fmt.Sprintf("domain %s accepts mail for any address", domain)
+		res.CatchAll = true
+		return res
+	}
 
-	// 5. SMTP mailbox check
-	return verifySMTP(mxHost, email.Address)
+	// 6. SMTP mailbox check
+	probe := ""
+	if catchAll == nil {
+		probe = randomLocalPart() + "@" + domain
+	}
+	check, err := verifySMTP(res.MXHost, email.Address, probe)
+	res.SMTPCode = check.code
+	res.CatchAll = check.catchAll
+	res.probed = check.probed
+	code := check.code
+	switch {
+	case err == nil && check.catchAll:
+		res.Result = VerificationRisky
+		res.Reason = fmt.Sprintf("domain %s accepts mail for any address", domain)
+	case err == nil:
+		res.Result = VerificationValid
+	case code >= 500:
+		res.Reason = err.Error()
+	default:
+		// no answer or a temporary (4xx) failure says nothing about the mailbox
+		res.Result = VerificationError
+		res.Reason = err.Error()
+	}
+	return res
+}
+
+// lookupMXHosts returns the MX hosts of a domain, most preferred first. It is
+// a variable so tests can stand in for DNS.
+var lookupMXHosts = func(domain string) ([]string, error) {
+	mxRecords, err := net.LookupMX(domain)
+	if err != nil {
+		return nil, err
+	}
+	hosts := make([]string, 0, len(mxRecords))
+	for _, mx := range mxRecords {
+		hosts = append(hosts, mx.Host)
+	}
+	return hosts, nil
+}
+
+// smtpCheck is what a verifySMTP session found out.
+type smtpCheck struct {
+	code     int  // reply code of the RCPT TO, or of the command that failed before it
+	probed   bool // the probe address got a definite answer
+	catchAll bool // the probe address was accepted
 }
 
 // verifySMTP dials the SMTP server and issues a RCPT TO command
-// to check if mailbox exists.
-func verifySMTP(mxHost, address string) error {
+// to check if mailbox exists. When probe is set, that address is tried first
+// in the same session; a server accepting it accepts anything.
+func verifySMTP(mxHost, address, probe string) (smtpCheck, error) {
+	var check smtpCheck
+
 	// connect to SMTP server
 	addr := fmt.Sprintf("%s:25", mxHost)
 	conn, err := net.DialTimeout("tcp", addr, 10*time.Second)
 	if err != nil {
-		return fmt.Errorf("unable to connect to SMTP server: %w", err)
+		return check, fmt.Errorf("unable to connect to SMTP server: %w", err)
 	}
 	defer conn.Close()
 
 	c, err := smtp.NewClient(conn, strings.TrimRight(mxHost, "."))
 	if err != nil {
-		return fmt.Errorf("SMTP handshake failed: %w", err)
+		check.code = smtpCode(err)
+		return check, fmt.Errorf("SMTP handshake failed: %w", err)
 	}
 	defer c.Quit()
 
 	// use a fake sender
 	sender := "noreply@example.com"
 	if err := c.Mail(sender); err != nil {
-		return fmt.Errorf("SMTP MAIL FROM failed: %w", err)
+		check.code = smtpCode(err)
+		return check, fmt.Errorf("SMTP MAIL FROM failed: %w", err)
+	}
+
+	if probe != "" {
+		err := c.Rcpt(probe)
+		switch {
+		case err == nil:
+			check.probed, check.catchAll = true, true
+		case smtpCode(err) >= 500:
+			check.probed = true
+		}
 	}
 
 	if err := c.Rcpt(address); err != nil {
 		// some servers reject RCPT TO for unknown users
-		return fmt.Errorf("email address rejected by server: %w", err)
+		check.code = smtpCode(err)
+		return check, fmt.Errorf("email address rejected by server: %w", err)
 	}
 
-	return nil
+	check.code = 250
+	return check, nil
+}
+
+// randomLocalPart returns a mailbox name no real person has.
+func randomLocalPart() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return "noexist-" + hex.EncodeToString(b)
+}
+
+// smtpCode extracts the reply code from an error returned by net/smtp.
+func smtpCode(err error) int {
+	var tpErr *textproto.Error
+	if errors.As(err, &tpErr) {
+		return tpErr.Code
+	}
+	return 0
 }
@@ -3,30 +3,87 @@ package emailer
 import (
 	"aiapply/models"
 	"bytes"
+	"crypto/rand"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"log"
-	"net/smtp"
+	"mime"
+	"net/mail"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 const (
-	smtpHost      = "smtp.gmail.com"
-	smtpPort      = "587"
-	username      = "jadhaoshreyash31@gmail.com" // your Gmail address
-	password      = "ttcubeiowzfeyebv"           // app password or OAuth token
 	mixedBoundary = "MIXED-BOUNDARY-123456"
 	altBoundary   = "ALT-BOUNDARY-654321"
 )
 
-// SendApplicationEmail sends your application (plain+HTML) and attaches resume & presentation.
-func SendApplicationEmail(to, position string, user models.User) error {
+// SentMessage identifies a delivered message so later mail can be threaded onto it.
+type SentMessage struct {
+	MessageID string
+	Subject   string
+}
+
+// Thread carries the headers that keep a follow-up in the original conversation.
+type Thread struct {
+	InReplyTo  string
+	References []string
+	Subject    string
+}
+
+// SendApplicationEmail renders tmpl with data, sends the application (plain+HTML) and attaches
+// the resume through the mail server configured for user. A non-empty trackingToken
+// adds open and click tracking to the HTML part.
+func SendApplicationEmail(db *gorm.DB, to, trackingToken string, user models.User, tmpl models.EmailTemplate, data TemplateData) (*SentMessage, error) {
+	return sendTemplated(db, to, trackingToken, user, tmpl, data, nil)
+}
+
+// SendFollowUpEmail sends tmpl as a reply in thread, reusing the original subject
+// so mail clients group it with the first email. No attachment is added.
+func SendFollowUpEmail(db *gorm.DB, to, trackingToken string, user models.User, tmpl models.EmailTemplate, data TemplateData, thread Thread) (*SentMessage, error) {
+	return sendTemplated(db, to, trackingToken, user, tmpl, data, &thread)
+}
+
+func sendTemplated(db *gorm.DB, to, trackingToken string, user models.User, tmpl models.EmailTemplate, data TemplateData, thread *Thread) (*SentMessage, error) {
+	sender, err := SenderForUser(db, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, plain, html, err := RenderTemplate(tmpl, data)
+	if err != nil {
+		return nil, fmt.Errorf("render template: %w", err)
+	}
+	html = AddTracking(html, trackingToken)
+	if thread != nil && thread.Subject != "" {
+		subject = thread.Subject
+		if !strings.HasPrefix(strings.ToLower(subject), "re:") {
+			subject = "Re: " + subject
+		}
+	}
+	messageID := newMessageID(sender.From())
 
 	// 1) Headers
-	subj := fmt.Sprintf("Subject: Application for %s - %s\r\n", position, user.Username)
-	headers := subj +
-		"MIME-Version: 1.0\r\n" +
+	subj := fmt.Sprintf("Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	headers := "From: " + sender.From() + "\r\n" +
+		"To: " + to + "\r\n" +
+		subj +
+		"Date: " + time.Now().Format(time.RFC1123Z) + "\r\n" +
+		"Message-ID: " + messageID + "\r\n"
+	if thread != nil {
+		if thread.InReplyTo != "" {
+			headers += "In-Reply-To: " + thread.InReplyTo + "\r\n"
+		}
+		if len(thread.References) > 0 {
+			headers += "References: " + strings.Join(thread.References, " ") + "\r\n"
+		}
+	}
+	headers += "MIME-Version: 1.0\r\n" +
 		fmt.Sprintf("Content-Type: multipart/mixed; boundary=%s\r\n", mixedBoundary) +
 		"\r\n"
 
@@ -41,75 +98,51 @@ func SendApplicationEmail(to, position string, user models.User) error {
 	// 2a) Plain text version
 	buf.WriteString(fmt.Sprintf("--%s\r\n", altBoundary))
 	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
-	buf.WriteString("Content-Transfer-Encoding: 7bit\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
 	buf.WriteString("\r\n")
-	buf.WriteString(plainBody(position, user))
+	buf.WriteString(plain)
 	buf.WriteString("\r\n")
 
 	// 2b) HTML version
 	buf.WriteString(fmt.Sprintf("--%s\r\n", altBoundary))
 	buf.WriteString("Content-Type: text/html; charset=utf-8\r\n")
-	buf.WriteString("Content-Transfer-Encoding: 7bit\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
 	buf.WriteString("\r\n")
-	buf.WriteString(htmlBody(position, user))
+	buf.WriteString(html)
 	buf.WriteString("\r\n")
 
 	// end of alternative
 	buf.WriteString(fmt.Sprintf("--%s--\r\n", altBoundary))
 
-	// 3) Attach resume.pdf
-	if err := attachFile(&buf, "resume.pdf"); err != nil {
-		return fmt.Errorf("attach resume: %w", err)
+	// 3) Attach resume.pdf to the first email of a thread only
+	if thread == nil {
+		if err := attachFile(&buf, "resume.pdf"); err != nil {
+			return nil, fmt.Errorf("attach resume: %w", err)
+		}
 	}
 
 	// 4) Closing mixed boundary
 	buf.WriteString(fmt.Sprintf("--%s--\r\n", mixedBoundary))
 
 	// 5) Send!
-	auth := smtp.PlainAuth("", username, password, smtpHost)
-	if err := smtp.SendMail(smtpHost+":"+smtpPort, auth, username, []string{to}, buf.Bytes()); err != nil {
+	if err := sender.Send([]string{to}, buf.Bytes()); err != nil {
 		log.Printf("Failed to send email to %s: %v", to, err)
-		return fmt.Errorf("sendMail: %w", err)
+		return nil, fmt.Errorf("sendMail: %w", err)
 	}
-	return nil
+	return &SentMessage{MessageID: messageID, Subject: subject}, nil
 }
 
-func plainBody(position string, user models.User) string {
-	return fmt.Sprintf(`Hope you're doing well.
-
-I'm reaching out to express my interest in the %s position at your company.
-
-I'm a frontend-focused full-stack developer who loves building scalable applications. As a Codeforces Expert (Top 1%%) and CodeChef 5‑star coder, I ranked 55 out of 40k participants in LeetCode Weekly Contest 148, showcasing my strong problem-solving skills.
-
-In my internships, I optimized API performance, implemented microservice architectures, and improved system efficiency to support growing loads. I believe my background aligns well with the needs of your team.
-
-I’d love to connect and learn more about any opportunities on your team. I've attached my resume and a presentation on my journey for your reference.
-
-Thank you for your time, and I look forward to hearing from you.
-
-Best regards,
-%s
-%s
-%s
-%s
-`, position, user.Username, user.LinkedInURL, user.GitHubURL, user.ResumeURL)
-}
-
-func htmlBody(position string, user models.User) string {
-	return fmt.Sprintf(`<html>
-  <body>
-    <p>Hope you're doing well.</p>
-    <p>I'm reaching out to express my interest in the <strong>%s</strong> position at your company.</p>
-    <p>I'm a <strong>frontend-focused full-stack developer</strong> who loves building scalable applications. As a <em>Codeforces Expert (Top 1%%)</em> and <em>CodeChef 5‑star coder</em>, I ranked 55 out of 40k participants in LeetCode Weekly Contest 148, showcasing my strong problem-solving skills.</p>
-    <p>In my internships, I optimized API performance, implemented microservice architectures, and improved system efficiency to support growing loads. I believe my background aligns well with the needs of your team.</p>
-    <p>I’d love to connect and learn more about any opportunities on your team. I've attached my resume and a presentation on my journey for your reference.</p>
-    <p>Thank you for your time, and I look forward to hearing from you.</p>
-    <p>Best regards,<br/>
-       <strong>%s</strong><br/>
-       <a href="%s">LinkedIn</a> | <a href="%s">GitHub</a> | <a href="%s">Resume</a>
-    </p>
-  </body>
-</html>`, position, user.Username, user.LinkedInURL, user.GitHubURL, user.ResumeURL)
+// newMessageID returns a unique Message-ID on the sender's domain.
+func newMessageID(from string) string {
+	domain := "localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(addr.Address, "@"); at >= 0 {
+			domain = addr.Address[at+1:]
+		}
+	}
+	b := make([]byte, 16)
+	rand.Read(b)
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(b), domain)
 }
 
 // attachFile reads a file, base64‑encodes it, and appends it as a mixed part.
@@ -2,22 +2,26 @@ package emailer
 
 import (
 	"fmt"
-	"net/smtp"
+
+	"gorm.io/gorm"
 )
 
-// SendGenericEmail sends a simple plain text email.
-func SendGenericEmail(to, subject, body string) error {
-	auth := smtp.PlainAuth("", username, password, smtpHost)
+// SendGenericEmail sends a simple plain text email through the mail server configured for userID.
+func SendGenericEmail(db *gorm.DB, userID uint, to, subject, body string) error {
+	sender, err := SenderForUser(db, userID)
+	if err != nil {
+		return err
+	}
 
-	msg := "To: " + to + "\r\n" +
+	msg := "From: " + sender.From() + "\r\n" +
+		"To: " + to + "\r\n" +
 		"Subject: " + subject + "\r\n" +
 		"\r\n" +
 		body
 
-	err := smtp.SendMail(smtpHost+":"+smtpPort, auth, username, []string{to}, []byte(msg))
+	err = sender.Send([]string{to}, []byte(msg))
 	if err != nil {
 		return fmt.Errorf("sendMail: %w", err)
 	}
 	return nil
 }
-
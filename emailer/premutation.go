@@ -2,25 +2,103 @@ package emailer
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"unicode"
 )
 
+// Permutation is a candidate address together with the pattern that built it.
+type Permutation struct {
+	Address string
+	Pattern string // e.g. "first.last", "f.last"
+}
+
 // Generate permutations as per your 10 templates
 func GeneratePermutations(first, last, domain string) []string {
-	firstLower := strings.ToLower(first)
-	lastLower := strings.ToLower(last)
-	firstInitial := strings.ToLower(string(first[0]))
-
-	return []string{
-		fmt.Sprintf("%s.%s@%s", firstLower, lastLower, domain),
-		fmt.Sprintf("%s@%s", firstLower, domain),
-		fmt.Sprintf("%s.%s@%s", firstInitial, lastLower, domain),
-		fmt.Sprintf("%s.%s@%s", firstLower, string(last[0]), domain),
-		fmt.Sprintf("%s%s@%s", firstLower, lastLower, domain),
-		fmt.Sprintf("%s.%s@%s", lastLower, firstLower, domain),
-		fmt.Sprintf("%s%s42@%s", firstLower, lastLower, domain),
-		fmt.Sprintf("%s%s@%s", firstLower, string(last[0]), domain),
-		fmt.Sprintf("%s_%s@%s", firstLower, lastLower, domain),
-		fmt.Sprintf("%s%s@%s", lastLower, firstInitial, domain),
+	var addresses []string
+	for _, p := range GeneratePatterns(first, last, domain) {
+		addresses = append(addresses, p.Address)
+	}
+	return addresses
+}
+
+// GeneratePatterns returns the same candidates as GeneratePermutations, named by pattern.
+// Punctuation is dropped from the names. When the last name is only an
+// initial, as in "Vaishali S.", only the patterns that need no more of it
+// are returned, and none at all without a first name.
+func GeneratePatterns(first, last, domain string) []Permutation {
+	firstLower := namePart(first)
+	lastLower := namePart(last)
+	if firstLower == "" {
+		return nil
+	}
+	firstInitial := string([]rune(firstLower)[:1])
+	if lastLower == "" {
+		return []Permutation{{fmt.Sprintf("%s@%s", firstLower, domain), "first"}}
+	}
+	lastInitial := string([]rune(lastLower)[:1])
+	if lastLower == lastInitial {
+		return []Permutation{
+			{fmt.Sprintf("%s@%s", firstLower, domain), "first"},
+			{fmt.Sprintf("%s.%s@%s", firstLower, lastInitial, domain), "first.l"},
+			{fmt.Sprintf("%s%s@%s", firstLower, lastInitial, domain), "firstl"},
+		}
+	}
+
+	return []Permutation{
+		{fmt.Sprintf("%s.%s@%s", firstLower, lastLower, domain), "first.last"},
+		{fmt.Sprintf("%s@%s", firstLower, domain), "first"},
+		{fmt.Sprintf("%s.%s@%s", firstInitial, lastLower, domain), "f.last"},
+		{fmt.Sprintf("%s.%s@%s", firstLower, lastInitial, domain), "first.l"},
+		{fmt.Sprintf("%s%s@%s", firstLower, lastLower, domain), "firstlast"},
+		{fmt.Sprintf("%s.%s@%s", lastLower, firstLower, domain), "last.first"},
+		{fmt.Sprintf("%s%s42@%s", firstLower, lastLower, domain), "firstlast42"},
+		{fmt.Sprintf("%s%s@%s", firstLower, lastInitial, domain), "firstl"},
+		{fmt.Sprintf("%s_%s@%s", firstLower, lastLower, domain), "first_last"},
+		{fmt.Sprintf("%s%s@%s", lastLower, firstInitial, domain), "lastf"},
+	}
+}
+
+// namePart lowercases a name and keeps only its letters and digits, so
+// "S." becomes "s" and "O'Brien" becomes "obrien".
+func namePart(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// RankPatterns orders candidates for a domain: patterns learned for that
+// domain come first in the given order, the rest follow by their global
+// success rate. Patterns without a rate count as 0.5, and ties keep the
+// order of GeneratePatterns.
+func RankPatterns(perms []Permutation, learned []string, rates map[string]float64) []Permutation {
+	learnedRank := make(map[string]int, len(learned))
+	for i, p := range learned {
+		if _, ok := learnedRank[p]; !ok {
+			learnedRank[p] = i
+		}
 	}
+	rate := func(pattern string) float64 {
+		if r, ok := rates[pattern]; ok {
+			return r
+		}
+		return 0.5
+	}
+
+	ranked := append([]Permutation(nil), perms...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		ri, iLearned := learnedRank[ranked[i].Pattern]
+		rj, jLearned := learnedRank[ranked[j].Pattern]
+		switch {
+		case iLearned && jLearned:
+			return ri < rj
+		case iLearned != jLearned:
+			return iLearned
+		}
+		return rate(ranked[i].Pattern) > rate(ranked[j].Pattern)
+	})
+	return ranked
 }
@@ -0,0 +1,49 @@
+package emailer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func addresses(perms []Permutation) []string {
+	var out []string
+	for _, p := range perms {
+		out = append(out, p.Address)
+	}
+	return out
+}
+
+func TestGeneratePatterns(t *testing.T) {
+	tests := []struct {
+		first, last string
+		want        []string
+	}{
+		// the hiring team of the saved LinkedIn page: the last name is an initial
+		{"Vaishali", "S.", []string{"vaishali@recro.io", "vaishali.s@recro.io", "vaishalis@recro.io"}},
+		{"Jane", "", []string{"jane@recro.io"}},
+		{"-", "Doe", nil},
+		{"Seán", "Ó'Brien", []string{
+			"seán.óbrien@recro.io", "seán@recro.io", "s.óbrien@recro.io", "seán.ó@recro.io", "seánóbrien@recro.io",
+			"óbrien.seán@recro.io", "seánóbrien42@recro.io", "seánó@recro.io", "seán_óbrien@recro.io", "óbriens@recro.io",
+		}},
+		{"Jane", "Doe", []string{
+			"jane.doe@recro.io", "jane@recro.io", "j.doe@recro.io", "jane.d@recro.io", "janedoe@recro.io",
+			"doe.jane@recro.io", "janedoe42@recro.io", "janed@recro.io", "jane_doe@recro.io", "doej@recro.io",
+		}},
+	}
+	for _, tt := range tests {
+		if got := addresses(GeneratePatterns(tt.first, tt.last, "recro.io")); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("GeneratePatterns(%q, %q) = %q, want %q", tt.first, tt.last, got, tt.want)
+		}
+	}
+}
+
+func TestGeneratePatternsAreValidAddresses(t *testing.T) {
+	for _, name := range [][2]string{{"Vaishali", "S."}, {"J.", "Doe-Smith"}, {"Anne-Marie", "O'Neil"}} {
+		for _, p := range GeneratePatterns(name[0], name[1], "recro.io") {
+			if res := verifyEmail(p.Address, func(string) ([]string, error) { return nil, nil }, nil); res.Reason == "invalid email format" {
+				t.Errorf("%s %s gives the malformed address %q", name[0], name[1], p.Address)
+			}
+		}
+	}
+}
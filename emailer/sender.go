@@ -0,0 +1,204 @@
+package emailer
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"aiapply/database"
+	"aiapply/models"
+	"aiapply/utils"
+
+	"gorm.io/gorm"
+)
+
+// ErrNoMailSettings is returned when a user has not configured a mail server yet.
+var ErrNoMailSettings = errors.New("no mail settings configured for user")
+
+// Sender delivers a fully formatted message on behalf of one user.
+type Sender interface {
+	// From returns the header value of the sending address.
+	From() string
+	Send(to []string, msg []byte) error
+}
+
+// SMTPSender sends mail through a user's own SMTP server.
+type SMTPSender struct {
+	Host        string
+	Port        int
+	FromAddress string
+	FromName    string
+	Username    string
+	Password    string
+	AuthMethod  string
+}
+
+// NewSMTPSender builds a sender from stored settings, decrypting the password.
+func NewSMTPSender(settings models.MailSettings) (*SMTPSender, error) {
+	password := ""
+	if settings.Password != "" {
+		var err error
+		password, err = utils.DecryptSecret(settings.Password)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt mail password: %w", err)
+		}
+	}
+	return &SMTPSender{
+		Host:        settings.Host,
+		Port:        settings.Port,
+		FromAddress: settings.FromAddress,
+		FromName:    settings.FromName,
+		Username:    settings.Username,
+		Password:    password,
+		AuthMethod:  settings.AuthMethod,
+	}, nil
+}
+
+// SenderForUser resolves the sender configured for userID.
+func SenderForUser(db *gorm.DB, userID uint) (Sender, error) {
+	settings, err := database.GetMailSettings(db, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoMailSettings
+		}
+		return nil, err
+	}
+	return NewSMTPSender(*settings)
+}
+
+func (s *SMTPSender) From() string {
+	addr := mail.Address{Name: s.FromName, Address: s.FromAddress}
+	return addr.String()
+}
+
+func (s *SMTPSender) Send(to []string, msg []byte) error {
+	c, err := s.dial()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if err := c.Mail(s.FromAddress); err != nil {
+		return fmt.Errorf("SMTP MAIL FROM failed: %w", err)
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("SMTP RCPT TO %s failed: %w", rcpt, err)
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA failed: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP DATA failed: %w", err)
+	}
+	return c.Quit()
+}
+
+// Test connects and authenticates without sending anything.
+func (s *SMTPSender) Test() error {
+	c, err := s.dial()
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	return c.Quit()
+}
+
+// dial opens an authenticated session. Port 465 uses implicit TLS, any other
+// port upgrades with STARTTLS when the server offers it.
+func (s *SMTPSender) dial() (*smtp.Client, error) {
+	if s.Host == "" || s.Port == 0 {
+		return nil, errors.New("mail host and port are required")
+	}
+	addr := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+	tlsConfig := &tls.Config{ServerName: s.Host}
+
+	var conn net.Conn
+	var err error
+	if s.Port == 465 {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: 10 * time.Second}, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = net.DialTimeout("tcp", addr, 10*time.Second)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to SMTP server: %w", err)
+	}
+
+	c, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SMTP handshake failed: %w", err)
+	}
+
+	if ok, _ := c.Extension("STARTTLS"); ok && s.Port != 465 {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+
+	auth, err := s.auth()
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+	return c, nil
+}
+
+func (s *SMTPSender) auth() (smtp.Auth, error) {
+	switch s.AuthMethod {
+	case "", "plain":
+		return smtp.PlainAuth("", s.Username, s.Password, s.Host), nil
+	case "login":
+		return &loginAuth{username: s.Username, password: s.Password}, nil
+	case "cram-md5":
+		return smtp.CRAMMD5Auth(s.Username, s.Password), nil
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unsupported auth method %q", s.AuthMethod)
+	}
+}
+
+// loginAuth implements the LOGIN mechanism still required by some providers.
+type loginAuth struct {
+	username, password string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	switch string(fromServer) {
+	case "Username:", "User Name\x00":
+		return []byte(a.username), nil
+	case "Password:", "Password\x00":
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected LOGIN challenge %q", fromServer)
+	}
+}
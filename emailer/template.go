@@ -0,0 +1,174 @@
+package emailer
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"aiapply/models"
+)
+
+// TemplateData holds the variables available to cold email templates, e.g.
+// {{.FirstName}} or {{.Company}}.
+type TemplateData struct {
+	FirstName     string // recipient
+	LastName      string
+	RecipientName string
+	Company       string
+	Role          string
+	SenderName    string
+	SenderTitle   string
+	Profile       string
+	Phone         string
+	Email         string
+	LinkedInURL   string
+	GitHubURL     string
+	ResumeURL     string
+}
+
+// NewTemplateData fills in the sender's profile fields for one recipient.
+func NewTemplateData(user models.User, recipientName, company, role string) TemplateData {
+	parts := strings.Fields(recipientName)
+	var first, last string
+	if len(parts) > 0 {
+		first = parts[0]
+		last = parts[len(parts)-1]
+	}
+	return TemplateData{
+		FirstName:     first,
+		LastName:      last,
+		RecipientName: strings.Join(parts, " "),
+		Company:       company,
+		Role:          role,
+		SenderName:    user.Username,
+		SenderTitle:   user.ProfileTitle,
+		Profile:       user.Profile,
+		Phone:         user.Phone,
+		Email:         user.Email,
+		LinkedInURL:   user.LinkedInURL,
+		GitHubURL:     user.GitHubURL,
+		ResumeURL:     user.ResumeURL,
+	}
+}
+
+// DefaultTemplate is used when an application does not pick a template.
+func DefaultTemplate() models.EmailTemplate {
+	return models.EmailTemplate{
+		Name:    "Default",
+		Subject: "Application for {{.Role}} - {{.SenderName}}",
+		PlainBody: `Hi {{.FirstName}},
+
+Hope you're doing well.
+
+I'm reaching out to express my interest in the {{.Role}} position at {{if .Company}}{{.Company}}{{else}}your company{{end}}.
+{{if .Profile}}
+{{.Profile}}
+{{end}}
+I'd love to connect and learn more about any opportunities on your team. I've attached my resume for your reference.
+
+Thank you for your time, and I look forward to hearing from you.
+
+Best regards,
+{{.SenderName}}
+{{- if .LinkedInURL}}
+{{.LinkedInURL}}{{end}}
+{{- if .GitHubURL}}
+{{.GitHubURL}}{{end}}
+{{- if .ResumeURL}}
+{{.ResumeURL}}{{end}}
+`,
+		HTMLBody: `<html>
+  <body>
+    <p>Hi {{.FirstName}},</p>
+    <p>Hope you're doing well.</p>
+    <p>I'm reaching out to express my interest in the <strong>{{.Role}}</strong> position at {{if .Company}}{{.Company}}{{else}}your company{{end}}.</p>
+    {{if .Profile}}<p>{{.Profile}}</p>{{end}}
+    <p>I'd love to connect and learn more about any opportunities on your team. I've attached my resume for your reference.</p>
+    <p>Thank you for your time, and I look forward to hearing from you.</p>
+    <p>Best regards,<br/>
+       <strong>{{.SenderName}}</strong><br/>
+       {{if .LinkedInURL}}<a href="{{.LinkedInURL}}">LinkedIn</a>{{end}}
+       {{if .GitHubURL}} | <a href="{{.GitHubURL}}">GitHub</a>{{end}}
+       {{if .ResumeURL}} | <a href="{{.ResumeURL}}">Resume</a>{{end}}
+    </p>
+  </body>
+</html>`,
+	}
+}
+
+// DefaultFollowUpTemplate is used for sequence steps without their own template.
+// Its subject is replaced by the original one when the follow-up is threaded.
+func DefaultFollowUpTemplate() models.EmailTemplate {
+	return models.EmailTemplate{
+		Name:    "Default follow-up",
+		Subject: "Application for {{.Role}} - {{.SenderName}}",
+		PlainBody: `Hi {{.FirstName}},
+
+I wanted to follow up on my note below about the {{.Role}} position{{if .Company}} at {{.Company}}{{end}}. I'd be glad to share more about my background if it would help.
+
+Best regards,
+{{.SenderName}}
+`,
+		HTMLBody: `<html>
+  <body>
+    <p>Hi {{.FirstName}},</p>
+    <p>I wanted to follow up on my note below about the <strong>{{.Role}}</strong> position{{if .Company}} at {{.Company}}{{end}}. I'd be glad to share more about my background if it would help.</p>
+    <p>Best regards,<br/>
+       <strong>{{.SenderName}}</strong>
+    </p>
+  </body>
+</html>`,
+	}
+}
+
+// ValidateTemplate parses every part of t without rendering it.
+func ValidateTemplate(t models.EmailTemplate) error {
+	if _, err := texttemplate.New("subject").Parse(t.Subject); err != nil {
+		return fmt.Errorf("subject: %w", err)
+	}
+	if _, err := texttemplate.New("plain").Parse(t.PlainBody); err != nil {
+		return fmt.Errorf("plain body: %w", err)
+	}
+	if _, err := htmltemplate.New("html").Parse(t.HTMLBody); err != nil {
+		return fmt.Errorf("html body: %w", err)
+	}
+	return nil
+}
+
+// RenderTemplate executes t with data. The HTML body goes through html/template,
+// so profile fields are escaped for their context and unsafe link schemes are
+// replaced. Line breaks are stripped from the subject to keep headers intact.
+func RenderTemplate(t models.EmailTemplate, data TemplateData) (subject, plain, html string, err error) {
+	if subject, err = renderText("subject", t.Subject, data); err != nil {
+		return "", "", "", err
+	}
+	subject = strings.Join(strings.Fields(subject), " ")
+
+	if plain, err = renderText("plain body", t.PlainBody, data); err != nil {
+		return "", "", "", err
+	}
+
+	tmpl, err := htmltemplate.New("html").Option("missingkey=error").Parse(t.HTMLBody)
+	if err != nil {
+		return "", "", "", fmt.Errorf("html body: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", "", "", fmt.Errorf("html body: %w", err)
+	}
+	return subject, plain, buf.String(), nil
+}
+
+func renderText(name, src string, data TemplateData) (string, error) {
+	tmpl, err := texttemplate.New(name).Option("missingkey=error").Parse(src)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return buf.String(), nil
+}
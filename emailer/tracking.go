@@ -0,0 +1,102 @@
+package emailer
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"html"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var hrefPattern = regexp.MustCompile(`(?i)(<a\b[^>]*?\bhref=")([^"]*)(")`)
+
+// NewTrackingToken returns a random token identifying one recipient.
+func NewTrackingToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// trackingBaseURL is the public address of this server, e.g. https://api.example.com.
+// Tracking is disabled while PUBLIC_BASE_URL is unset.
+func trackingBaseURL() string {
+	return strings.TrimRight(os.Getenv("PUBLIC_BASE_URL"), "/")
+}
+
+// trackingSecret is the key click links are signed with, TRACKING_SECRET or
+// else JWT_SECRET.
+func trackingSecret() string {
+	if secret := os.Getenv("TRACKING_SECRET"); secret != "" {
+		return secret
+	}
+	return os.Getenv("JWT_SECRET")
+}
+
+// CheckTracking reports a tracking setup the server must not start with:
+// links rewritten without a secret could be forged into open redirects.
+func CheckTracking() error {
+	if trackingBaseURL() != "" && trackingSecret() == "" {
+		return errors.New("PUBLIC_BASE_URL is set but neither TRACKING_SECRET nor JWT_SECRET is, so click links cannot be signed")
+	}
+	return nil
+}
+
+// ClickSignature authenticates a redirect target so the click endpoint cannot
+// be used as an open redirect. It is empty when there is no secret to sign with.
+func ClickSignature(token, target string) string {
+	secret := trackingSecret()
+	if secret == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(token + "\n" + target))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidClickSignature reports whether sig was produced by ClickSignature.
+func ValidClickSignature(token, target, sig string) bool {
+	expected := ClickSignature(token, target)
+	return expected != "" && hmac.Equal([]byte(expected), []byte(sig))
+}
+
+// AddTracking rewrites every http(s) link in body to go through the click
+// endpoint and appends an open tracking pixel. Links are left alone when
+// there is no secret to sign them with.
+func AddTracking(body, token string) string {
+	base := trackingBaseURL()
+	if base == "" || token == "" {
+		return body
+	}
+
+	if trackingSecret() != "" {
+		body = rewriteLinks(body, base, token)
+	}
+
+	pixel := `<img src="` + base + "/t/open/" + token + `" width="1" height="1" alt="" style="display:none"/>`
+	if i := strings.LastIndex(strings.ToLower(body), "</body>"); i >= 0 {
+		return body[:i] + pixel + body[i:]
+	}
+	return body + pixel
+}
+
+// rewriteLinks points the http(s) links of body at the click endpoint.
+func rewriteLinks(body, base, token string) string {
+	return hrefPattern.ReplaceAllStringFunc(body, func(m string) string {
+		parts := hrefPattern.FindStringSubmatch(m)
+		target := html.UnescapeString(parts[2])
+		lower := strings.ToLower(target)
+		if !strings.HasPrefix(lower, "http://") && !strings.HasPrefix(lower, "https://") {
+			return m
+		}
+		q := url.Values{}
+		q.Set("u", target)
+		q.Set("sig", ClickSignature(token, target))
+		tracked := base + "/t/click/" + token + "?" + q.Encode()
+		return parts[1] + html.EscapeString(tracked) + parts[3]
+	})
+}
@@ -0,0 +1,71 @@
+package emailer
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const trackedBody = `<p>See <a href="https://acme.com/jobs?id=1&amp;ref=2">the role</a> or <a href="mailto:me@example.com">mail me</a>.</p></body>`
+
+func TestAddTracking(t *testing.T) {
+	t.Setenv("PUBLIC_BASE_URL", "https://api.example.com/")
+	t.Setenv("TRACKING_SECRET", "s3cret")
+	t.Setenv("JWT_SECRET", "")
+
+	got := AddTracking(trackedBody, "tok")
+	link := regexp.MustCompile(`href="(https://api\.example\.com/t/click/tok\?[^"]*)"`).FindStringSubmatch(got)
+	if link == nil {
+		t.Fatalf("link not rewritten: %s", got)
+	}
+	u, err := url.Parse(strings.ReplaceAll(link[1], "&amp;", "&"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := u.Query().Get("u")
+	if target != "https://acme.com/jobs?id=1&ref=2" {
+		t.Errorf("target = %q", target)
+	}
+	if !ValidClickSignature("tok", target, u.Query().Get("sig")) {
+		t.Error("signature of the rewritten link does not validate")
+	}
+	if ValidClickSignature("tok", "https://evil.test", u.Query().Get("sig")) {
+		t.Error("signature validates another target")
+	}
+	if !strings.Contains(got, `href="mailto:me@example.com"`) {
+		t.Error("mailto link rewritten")
+	}
+	if !strings.Contains(got, `<img src="https://api.example.com/t/open/tok"`) || !strings.HasSuffix(got, "</body>") {
+		t.Errorf("pixel missing or misplaced: %s", got)
+	}
+}
+
+func TestTrackingWithoutSecret(t *testing.T) {
+	t.Setenv("PUBLIC_BASE_URL", "https://api.example.com")
+	t.Setenv("TRACKING_SECRET", "")
+	t.Setenv("JWT_SECRET", "")
+
+	if err := CheckTracking(); err == nil {
+		t.Error("CheckTracking accepts tracking without a secret")
+	}
+	if sig := ClickSignature("tok", "https://acme.com"); sig != "" {
+		t.Errorf("ClickSignature without a secret = %q", sig)
+	}
+	if ValidClickSignature("tok", "https://acme.com", "") {
+		t.Error("empty signature validates without a secret")
+	}
+	if got := AddTracking(trackedBody, "tok"); !strings.Contains(got, `href="https://acme.com/jobs?id=1&amp;ref=2"`) || strings.Contains(got, "/t/click/") {
+		t.Errorf("links rewritten without a secret: %s", got)
+	}
+
+	t.Setenv("JWT_SECRET", "jwt")
+	if err := CheckTracking(); err != nil {
+		t.Errorf("CheckTracking with JWT_SECRET = %v", err)
+	}
+	t.Setenv("PUBLIC_BASE_URL", "")
+	t.Setenv("JWT_SECRET", "")
+	if err := CheckTracking(); err != nil {
+		t.Errorf("CheckTracking with tracking off = %v", err)
+	}
+}
@@ -0,0 +1,250 @@
+package emailer
+
+import (
+	"errors"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"aiapply/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	DefaultPositiveTTL = 30 * 24 * time.Hour
+	DefaultNegativeTTL = 24 * time.Hour
+)
+
+// VerificationCache remembers verification results so an address or domain is
+// not probed again until its entry expires. Entries live in Postgres and are
+// kept in memory in front of it. Valid results and domains with MX hosts use
+// the positive TTL, rejected or risky addresses and domains without MX hosts
+// the negative one. Errors are never cached.
+type VerificationCache struct {
+	db          *gorm.DB
+	positiveTTL time.Duration
+	negativeTTL time.Duration
+
+	mu        sync.Mutex
+	addresses map[string]models.VerifiedAddress
+	domains   map[string]models.VerifiedDomain
+}
+
+// NewVerificationCache returns a cache backed by db. The TTLs are read from
+// VERIFY_CACHE_TTL and VERIFY_CACHE_NEGATIVE_TTL (e.g. "720h"), falling back
+// to DefaultPositiveTTL and DefaultNegativeTTL.
+func NewVerificationCache(db *gorm.DB) *VerificationCache {
+	return &VerificationCache{
+		db:          db,
+		positiveTTL: envDuration("VERIFY_CACHE_TTL", DefaultPositiveTTL),
+		negativeTTL: envDuration("VERIFY_CACHE_NEGATIVE_TTL", DefaultNegativeTTL),
+		addresses:   make(map[string]models.VerifiedAddress),
+		domains:     make(map[string]models.VerifiedDomain),
+	}
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		log.Printf("Ignoring invalid %s %q", key, value)
+		return fallback
+	}
+	return d
+}
+
+// Verify returns the cached result for address, or verifies it and caches
+// the outcome.
+func (c *VerificationCache) Verify(address string) VerificationResult {
+	key := strings.ToLower(strings.TrimSpace(address))
+	if entry, ok := c.address(key); ok {
+		return VerificationResult{
+			Address:  address,
+			Result:   entry.Result,
+			Reason:   entry.Reason,
+			MXHost:   entry.MXHost,
+			SMTPCode: entry.SMTPCode,
+			CatchAll: entry.Result == VerificationRisky,
+		}
+	}
+
+	var catchAll *bool
+	if at := strings.LastIndex(key, "@"); at >= 0 {
+		if entry, ok := c.Domain(key[at+1:]); ok {
+			catchAll = entry.CatchAll
+		}
+	}
+
+	res := verifyEmail(address, c.LookupMX, catchAll)
+	if res.probed {
+		c.SetCatchAll(key[strings.LastIndex(key, "@")+1:], res.CatchAll)
+	}
+	if res.Result == VerificationError {
+		return res
+	}
+
+	ttl := c.positiveTTL
+	if res.Result != VerificationValid {
+		ttl = c.negativeTTL
+	}
+	now := time.Now()
+	c.storeAddress(models.VerifiedAddress{
+		Address:   key,
+		Result:    res.Result,
+		Reason:    res.Reason,
+		MXHost:    res.MXHost,
+		SMTPCode:  res.SMTPCode,
+		CheckedAt: now,
+		ExpiresAt: now.Add(ttl),
+	})
+	return res
+}
+
+// LookupMX returns the MX hosts of domain, from the cache when possible.
+func (c *VerificationCache) LookupMX(domain string) ([]string, error) {
+	key := strings.ToLower(domain)
+	if entry, ok := c.Domain(key); ok {
+		if entry.MXHosts == "" {
+			return nil, nil
+		}
+		return strings.Split(entry.MXHosts, ","), nil
+	}
+
+	hosts, err := lookupMXHosts(key)
+	var dnsErr *net.DNSError
+	if err != nil && !(errors.As(err, &dnsErr) && dnsErr.IsNotFound) {
+		return nil, err
+	}
+
+	ttl := c.positiveTTL
+	if len(hosts) == 0 {
+		ttl = c.negativeTTL
+	}
+	now := time.Now()
+	c.storeDomain(models.VerifiedDomain{
+		Domain:    key,
+		MXHosts:   strings.Join(hosts, ","),
+		CheckedAt: now,
+		ExpiresAt: now.Add(ttl),
+	})
+	return hosts, nil
+}
+
+// Domain returns the unexpired cache entry of a domain.
+func (c *VerificationCache) Domain(domain string) (models.VerifiedDomain, bool) {
+	key := strings.ToLower(domain)
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.domains[key]
+	c.mu.Unlock()
+	if ok && entry.ExpiresAt.After(now) {
+		return entry, true
+	}
+
+	if err := c.db.Where("domain = ? AND expires_at > ?", key, now).First(&entry).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Failed to read verification cache for %s: %v", key, err)
+		}
+		return models.VerifiedDomain{}, false
+	}
+	c.mu.Lock()
+	c.domains[key] = entry
+	c.mu.Unlock()
+	return entry, true
+}
+
+// SetCatchAll records whether a domain accepts mail for any address. The
+// domain keeps its MX hosts and expiry when it is already cached.
+func (c *VerificationCache) SetCatchAll(domain string, catchAll bool) {
+	entry, ok := c.Domain(domain)
+	if !ok {
+		now := time.Now()
+		entry = models.VerifiedDomain{Domain: strings.ToLower(domain), CheckedAt: now, ExpiresAt: now.Add(c.positiveTTL)}
+	}
+	entry.CatchAll = &catchAll
+	c.storeDomain(entry)
+}
+
+// Invalidate drops the cached result of one address.
+func (c *VerificationCache) Invalidate(address string) error {
+	key := strings.ToLower(strings.TrimSpace(address))
+	c.mu.Lock()
+	delete(c.addresses, key)
+	c.mu.Unlock()
+	return c.db.Where("address = ?", key).Delete(&models.VerifiedAddress{}).Error
+}
+
+// InvalidateDomain drops the cached entry of a domain and of every address
+// in it.
+func (c *VerificationCache) InvalidateDomain(domain string) error {
+	key := strings.ToLower(strings.TrimSpace(domain))
+	suffix := "@" + key
+
+	c.mu.Lock()
+	delete(c.domains, key)
+	for address := range c.addresses {
+		if strings.HasSuffix(address, suffix) {
+			delete(c.addresses, address)
+		}
+	}
+	c.mu.Unlock()
+
+	return c.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("domain = ?", key).Delete(&models.VerifiedDomain{}).Error; err != nil {
+			return err
+		}
+		// compared as a suffix rather than with LIKE, whose % and _ a domain
+		// could smuggle in
+		return tx.Where("RIGHT(address, ?) = ?", utf8.RuneCountInString(suffix), suffix).Delete(&models.VerifiedAddress{}).Error
+	})
+}
+
+func (c *VerificationCache) address(key string) (models.VerifiedAddress, bool) {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.addresses[key]
+	c.mu.Unlock()
+	if ok && entry.ExpiresAt.After(now) {
+		return entry, true
+	}
+
+	if err := c.db.Where("address = ? AND expires_at > ?", key, now).First(&entry).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Failed to read verification cache for %s: %v", key, err)
+		}
+		return models.VerifiedAddress{}, false
+	}
+	c.mu.Lock()
+	c.addresses[key] = entry
+	c.mu.Unlock()
+	return entry, true
+}
+
+func (c *VerificationCache) storeAddress(entry models.VerifiedAddress) {
+	c.mu.Lock()
+	c.addresses[entry.Address] = entry
+	c.mu.Unlock()
+	if err := c.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&entry).Error; err != nil {
+		log.Printf("Failed to cache verification of %s: %v", entry.Address, err)
+	}
+}
+
+func (c *VerificationCache) storeDomain(entry models.VerifiedDomain) {
+	c.mu.Lock()
+	c.domains[entry.Domain] = entry
+	c.mu.Unlock()
+	if err := c.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&entry).Error; err != nil {
+		log.Printf("Failed to cache domain %s: %v", entry.Domain, err)
+	}
+}
@@ -0,0 +1,53 @@
+package emailer
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"aiapply/database/dbtest"
+	"aiapply/models"
+)
+
+func TestVerifyEmailMXLookup(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		hosts  []string
+		result string
+	}{
+		{"timeout", &net.DNSError{Err: "i/o timeout", Name: "acme.com", IsTimeout: true}, nil, VerificationError},
+		{"servfail", &net.DNSError{Err: "server misbehaving", Name: "acme.com", IsTemporary: true}, nil, VerificationError},
+		{"other error", errors.New("network is unreachable"), nil, VerificationError},
+		{"no such host", &net.DNSError{Err: "no such host", Name: "acme.com", IsNotFound: true}, nil, VerificationInvalid},
+		{"no hosts", nil, nil, VerificationInvalid},
+	}
+	for _, tt := range tests {
+		lookup := func(string) ([]string, error) { return tt.hosts, tt.err }
+		if res := verifyEmail("jane@acme.com", lookup, nil); res.Result != tt.result {
+			t.Errorf("%s: result = %s (%s), want %s", tt.name, res.Result, res.Reason, tt.result)
+		}
+	}
+}
+
+func TestVerifyDoesNotCacheFailedLookup(t *testing.T) {
+	db := dbtest.Open(t, &models.VerifiedAddress{}, &models.VerifiedDomain{})
+	original := lookupMXHosts
+	t.Cleanup(func() { lookupMXHosts = original })
+	lookupMXHosts = func(string) ([]string, error) {
+		return nil, &net.DNSError{Err: "i/o timeout", Name: "acme.com", IsTimeout: true}
+	}
+
+	c := NewVerificationCache(db)
+	if res := c.Verify("jane@acme.com"); res.Result != VerificationError {
+		t.Fatalf("Verify = %s (%s), want %s", res.Result, res.Reason, VerificationError)
+	}
+
+	var addresses, domains int64
+	db.Model(&models.VerifiedAddress{}).Count(&addresses)
+	db.Model(&models.VerifiedDomain{}).Count(&domains)
+	if addresses != 0 || domains != 0 || len(c.addresses) != 0 || len(c.domains) != 0 {
+		t.Errorf("cached %d addresses and %d domains (%d and %d in memory), want none",
+			addresses, domains, len(c.addresses), len(c.domains))
+	}
+}
@@ -1,26 +1,66 @@
 import { Opportunity } from "@/hooks/useJobOpportunities";
 import { profile } from "console";
 
-interface RawOpportunity {
-  ID: string;
-  Role: string;
-  CompanyName: string;
-  CompanyPhotoURL: string;
-  Location: string;
-  Salary: string;
-  Duration: string;
-  Mode: string;
-  StartDate: string;
-  OfficeLocation: string;
-  ApplyBy: string;
-  PostedAgo: string;
-  Type: string;
-  ApplyURL: string;
-  Skills: string[];
-  Level: string;
-  Company: string;
+interface ScrapedJob {
+  id: string;
+  platform: string;
+  role: string;
+  company: string;
+  location: string;
+  salary: string;
+  url: string;
+  skills: string[] | null;
+  posted_at: string | null;
+  extras?: Record<string, string>;
 }
 
+interface ScrapeDiagnostics {
+  platform: string;
+  selector_version: number;
+  cards: number;
+  jobs: number;
+  fill_rates: Record<string, number>;
+  warnings: string[] | null;
+}
+
+interface ScrapeResponse {
+  jobs: ScrapedJob[];
+  diagnostics: ScrapeDiagnostics;
+}
+
+interface ScrapeDetection {
+  platform: string;
+  score: number;
+  signals: string[];
+}
+
+interface ScrapeUploadResult {
+  file: string;
+  platform?: string;
+  detection?: ScrapeDetection;
+  jobs: ScrapedJob[];
+  diagnostics?: ScrapeDiagnostics;
+  error?: string;
+}
+
+const toOpportunity = (job: ScrapedJob): Opportunity => ({
+  id: job.id,
+  name: job.role,
+  company: job.company,
+  salary: job.salary,
+  location: job.location,
+  imageUrl: job.extras?.company_photo_url,
+  applyUrl: job.url,
+  duration: job.extras?.duration,
+  mode: job.extras?.mode,
+  startDate: job.extras?.start_date,
+  officeLocation: job.extras?.office_location,
+  applyBy: job.extras?.apply_by,
+  postedAgo: job.extras?.posted_ago,
+  skills: job.skills ?? undefined,
+  level: job.extras?.level,
+});
+
 const API_BASE_URL = "http://localhost:8090/api";
 const API_AUTH_URL = "http://localhost:8090";
 
@@ -32,7 +72,7 @@ const getAuthToken = () => {
 const handleResponse = async (response: Response) => {
   if (!response.ok) {
     const error = await response.json();
-    throw new Error(error.message || "Something went wrong");
+    throw new Error(error.error || error.message || "Something went wrong");
   }
   return response.json();
 };
@@ -49,24 +89,8 @@ export const scrapeCuvette = async (file: File): Promise<Opportunity[]> => {
     body: formData,
   });
 
-  const data: RawOpportunity[] = await handleResponse(response);
-  return data.map((item) => ({
-    id: item.ID,
-    name: item.Role,
-    company: item.CompanyName,
-    salary: item.Salary,
-    location: item.Location,
-    imageUrl: item.CompanyPhotoURL,
-    duration: item.Duration,
-    mode: item.Mode,
-    startDate: item.StartDate,
-    officeLocation: item.OfficeLocation,
-    applyBy: item.ApplyBy,
-    postedAgo: item.PostedAgo,
-    skills: item.Skills,
-    level: item.Level,
-    applyUrl: item.ApplyURL,
-  }));
+  const data: ScrapeResponse = await handleResponse(response);
+  return data.jobs.map(toOpportunity);
 };
 
 export const scrapeLinkedIn = async (file: File): Promise<Opportunity[]> => {
@@ -81,14 +105,8 @@ export const scrapeLinkedIn = async (file: File): Promise<Opportunity[]> => {
     body: formData,
   });
 
-  const data: RawOpportunity[] = await handleResponse(response);
-  return data.map((item) => ({
-    id: item.ID,
-    name: item.Role,
-    company: item.Company,
-    salary: item.Salary,
-    location: item.Location,
-  }));
+  const data: ScrapeResponse = await handleResponse(response);
+  return data.jobs.map(toOpportunity);
 };
 
 export const scrapeWellfound = async (file: File): Promise<Opportunity[]> => {
@@ -103,7 +121,28 @@ export const scrapeWellfound = async (file: File): Promise<Opportunity[]> => {
     body: formData,
   });
 
-  return handleResponse(response);
+  const data: ScrapeResponse = await handleResponse(response);
+  return data.jobs.map(toOpportunity);
+};
+
+// Scrapes pages of any supported platform, including zip archives of them
+export const scrapeFiles = async (files: File[]) => {
+  const formData = new FormData();
+  files.forEach((file) => formData.append("files", file));
+
+  const response = await fetch(`${API_BASE_URL}/scrape`, {
+    method: "POST",
+    headers: {
+      Authorization: getAuthToken(),
+    },
+    body: formData,
+  });
+
+  const data: { results: ScrapeUploadResult[] } = await handleResponse(response);
+  return data.results.map((result) => ({
+    ...result,
+    opportunities: result.jobs.map(toOpportunity),
+  }));
 };
 
 export const fetchAnalytics = async () => {
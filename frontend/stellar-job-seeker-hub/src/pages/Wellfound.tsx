@@ -34,17 +34,7 @@ const Wellfound = () => {
     setIsUploading(true);
 
     try {
-      const data = await scrapeWellfound(selectedFile);
-      console.log("Scraped Wellfound data:", data);
-      const opportunities: Opportunity[] = data.map((job: any) => ({
-        id: job.ID,
-        name: job.Role,
-        company: job.CompanyName,
-        salary: job.Salary,
-        location: job.Location,
-        imageUrl: job.CompanyPhotoURL,
-        applyUrl: job.CompanyURL,
-      }));
+      const opportunities: Opportunity[] = await scrapeWellfound(selectedFile);
       setWellfoundOpportunities(opportunities);
       toast({
         title: "Success!",
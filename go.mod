@@ -13,7 +13,11 @@ require (
 	gorm.io/gorm v1.30.0
 )
 
-require github.com/jinzhu/gorm v1.9.16
+require (
+	github.com/emersion/go-imap v1.2.1
+	github.com/jinzhu/gorm v1.9.16
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	cloud.google.com/go/auth v0.16.2 // indirect
@@ -24,6 +28,8 @@ require (
 	github.com/chromedp/cdproto v0.0.0-20250724212937-08a3db8b4327 // indirect
 	github.com/chromedp/sysutil v1.1.0 // indirect
 	github.com/cloudwego/base64x v0.1.5 // indirect
+	github.com/emersion/go-message v0.18.2 // indirect
+	github.com/emersion/go-sasl v0.0.0-20200509203442-7bfe0ed36a21 // indirect
 	github.com/felixge/httpsnoop v1.0.4 // indirect
 	github.com/gabriel-vasile/mimetype v1.4.9 // indirect
 	github.com/gin-contrib/sse v1.1.0 // indirect
@@ -69,13 +75,12 @@ require (
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250603155806-513f23925822 // indirect
 	google.golang.org/grpc v1.73.0 // indirect
 	google.golang.org/protobuf v1.36.6 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 require (
 	github.com/PuerkitoBio/goquery v1.10.3
-	github.com/andybalholm/cascadia v1.3.3 // indirect
-	github.com/chromedp/chromedp v0.14.0
-	golang.org/x/net v0.41.0 // indirect
+	github.com/andybalholm/cascadia v1.3.3
+	github.com/chromedp/chromedp v0.14.2
+	golang.org/x/net v0.41.0
 	google.golang.org/api v0.242.0
 )
@@ -0,0 +1,143 @@
+package greenhouse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Job is one listing of the Greenhouse job board API,
+// boards-api.greenhouse.io/v1/boards/{board}/jobs.
+type Job struct {
+	ID             int64      `json:"id"`
+	Title          string     `json:"title"`
+	CompanyName    string     `json:"company_name"`
+	AbsoluteURL    string     `json:"absolute_url"`
+	RequisitionID  string     `json:"requisition_id"`
+	Location       Location   `json:"location"`
+	Offices        []Office   `json:"offices"`
+	Departments    []Named    `json:"departments"`
+	Metadata       []Metadata `json:"metadata"`
+	Content        string     `json:"content"` // escaped HTML, with ?content=true
+	PayInputRanges []PayRange `json:"pay_input_ranges"`
+	UpdatedAt      string     `json:"updated_at"`
+	FirstPublished string     `json:"first_published"`
+}
+
+type Location struct {
+	Name string `json:"name"`
+}
+
+type Office struct {
+	Name     string `json:"name"`
+	Location string `json:"location"`
+}
+
+type Named struct {
+	Name string `json:"name"`
+}
+
+// Metadata is a custom field of the board, such as "Employment Type".
+type Metadata struct {
+	Name  string          `json:"name"`
+	Value json.RawMessage `json:"value"`
+}
+
+// PayRange is a salary range, given with ?pay_transparency=true.
+type PayRange struct {
+	MinCents     int64  `json:"min_cents"`
+	MaxCents     int64  `json:"max_cents"`
+	CurrencyType string `json:"currency_type"`
+	Title        string `json:"title"`
+}
+
+type board struct {
+	Jobs []Job `json:"jobs"`
+}
+
+// ScrapeBoard reads a Greenhouse board listing: the API response, a bare
+// array of its jobs or a single job.
+func ScrapeBoard(reader io.Reader) ([]Job, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+
+	var jobs []Job
+	switch {
+	case len(data) > 0 && data[0] == '[':
+		err = json.Unmarshal(data, &jobs)
+	case bytes.Contains(data, []byte(`"jobs"`)):
+		var b board
+		err = json.Unmarshal(data, &b)
+		jobs = b.Jobs
+	default:
+		var job Job
+		err = json.Unmarshal(data, &job)
+		jobs = []Job{job}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse Greenhouse JSON: %w", err)
+	}
+	return jobs, nil
+}
+
+// Board is the board name in the job's link, e.g. "acme" in
+// boards.greenhouse.io/acme/jobs/123.
+func (j Job) Board() string {
+	u, err := url.Parse(j.AbsoluteURL)
+	if err != nil || !strings.HasSuffix(u.Host, "greenhouse.io") {
+		// boards on the company's own site link there instead
+		return ""
+	}
+	if board := u.Query().Get("for"); board != "" {
+		return board
+	}
+	board, _, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	return board
+}
+
+// Field returns the value of a metadata field by name, joining lists.
+func (j Job) Field(name string) string {
+	for _, m := range j.Metadata {
+		if !strings.EqualFold(m.Name, name) {
+			continue
+		}
+		var value any
+		if json.Unmarshal(m.Value, &value) != nil {
+			return ""
+		}
+		switch v := value.(type) {
+		case string:
+			return strings.TrimSpace(v)
+		case []any:
+			var values []string
+			for _, e := range v {
+				if s, ok := e.(string); ok && s != "" {
+					values = append(values, s)
+				}
+			}
+			return strings.Join(values, ", ")
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+	return ""
+}
+
+// PostedAt is when the job was first published, or last updated when the
+// board does not say.
+func (j Job) PostedAt() *time.Time {
+	for _, value := range []string{j.FirstPublished, j.UpdatedAt} {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package greenhouse
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"aiapply/scraper"
+)
+
+func init() {
+	scraper.Register(Scraper{})
+}
+
+// Scraper maps Greenhouse job board listings into scraper jobs.
+type Scraper struct{}
+
+func (Scraper) Platform() string { return "greenhouse" }
+
+func (Scraper) Fingerprint() scraper.Fingerprint {
+	return scraper.Fingerprint{
+		Strings: []string{`"absolute_url"`, "greenhouse.io/"},
+	}
+}
+
+var boardName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// BoardURL accepts a board name, a boards.greenhouse.io or
+// job-boards.greenhouse.io page, an embedded board link or an API link.
+func (Scraper) BoardURL(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	board := ref
+	if !boardName.MatchString(ref) {
+		if !strings.Contains(ref, "://") {
+			ref = "https://" + ref
+		}
+		u, err := url.Parse(ref)
+		if err != nil || !onHost(u, "greenhouse.io") {
+			return "", fmt.Errorf("not a Greenhouse board: %s", ref)
+		}
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		switch {
+		case u.Query().Get("for") != "":
+			board = u.Query().Get("for")
+		case strings.HasPrefix(u.Host, "boards-api.") && len(parts) >= 3 && parts[0] == "v1" && parts[1] == "boards":
+			board = parts[2]
+		default:
+			board = parts[0]
+		}
+	}
+	if !boardName.MatchString(board) {
+		return "", fmt.Errorf("not a Greenhouse board: %s", ref)
+	}
+	return "https://boards-api.greenhouse.io/v1/boards/" + board + "/jobs?content=true&pay_transparency=true", nil
+}
+
+// onHost reports whether u is on domain or one of its subdomains.
+func onHost(u *url.URL, domain string) bool {
+	host := strings.ToLower(u.Hostname())
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+func (Scraper) CountCards(r io.Reader) (int, error) {
+	jobs, err := ScrapeBoard(r)
+	return len(jobs), err
+}
+
+func (Scraper) Scrape(r io.Reader) ([]scraper.Job, error) {
+	listings, err := ScrapeBoard(r)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]scraper.Job, 0, len(listings))
+	for _, l := range listings {
+		if l.Title == "" {
+			continue
+		}
+		job := scraper.Job{
+			Platform: "greenhouse",
+			Role:     strings.TrimSpace(l.Title),
+			Company:  strings.TrimSpace(l.CompanyName),
+			Location: location(l),
+			Salary:   salary(l.PayInputRanges),
+			URL:      l.AbsoluteURL,
+			PostedAt: l.PostedAt(),
+		}
+		if l.ID != 0 {
+			job.ID = strconv.FormatInt(l.ID, 10)
+		}
+		if job.Company == "" {
+			job.Company = scraper.BoardCompany(l.Board())
+		}
+
+		var departments []string
+		for _, d := range l.Departments {
+			departments = append(departments, d.Name)
+		}
+		job.SetExtra("board", l.Board())
+		job.SetExtra("department", strings.Join(departments, ", "))
+		job.SetExtra("requisition_id", l.RequisitionID)
+		job.SetExtra("employment_type", l.Field("Employment Type"))
+		job.SetExtra("mode", l.Field("Workplace Type"))
+		job.SetExtra("description", scraper.PlainText(l.Content))
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// location prefers the location caption; boards that leave it empty still
+// name the offices the job belongs to.
+func location(l Job) string {
+	if name := strings.TrimSpace(l.Location.Name); name != "" {
+		return name
+	}
+	var offices []string
+	for _, o := range l.Offices {
+		place := o.Location
+		if place == "" {
+			place = o.Name
+		}
+		if place != "" {
+			offices = append(offices, place)
+		}
+	}
+	return strings.Join(offices, " • ")
+}
+
+// salary prints the first pay range, e.g. "USD 150,000 - 190,000". The
+// period is left for ParseCompensation to guess from the amounts.
+func salary(ranges []PayRange) string {
+	for _, r := range ranges {
+		if r.MaxCents == 0 && r.MinCents == 0 {
+			continue
+		}
+		text := scraper.FormatAmount(float64(r.MinCents) / 100)
+		if r.MaxCents > r.MinCents {
+			text += " - " + scraper.FormatAmount(float64(r.MaxCents)/100)
+		}
+		if r.CurrencyType != "" {
+			text = r.CurrencyType + " " + text
+		}
+		return text
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package greenhouse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"aiapply/scraper"
+)
+
+// stubClient sends every request to a local server, keeping the path and
+// query, and records the URLs it was asked for.
+type stubClient struct {
+	server    *httptest.Server
+	requested []string
+}
+
+func (c *stubClient) Do(req *http.Request) (*http.Response, error) {
+	c.requested = append(c.requested, req.URL.String())
+	local := req.Clone(req.Context())
+	local.URL.Scheme = "http"
+	local.URL.Host = strings.TrimPrefix(c.server.URL, "http://")
+	return c.server.Client().Do(local)
+}
+
+// boardServer serves the saved Wingify board and 404 for any other.
+func boardServer(t *testing.T) *stubClient {
+	board, err := os.ReadFile("greenhouse.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/boards/wingify/jobs" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(board)
+	}))
+	t.Cleanup(server.Close)
+	return &stubClient{server: server}
+}
+
+func TestBoardURL(t *testing.T) {
+	const api = "https://boards-api.greenhouse.io/v1/boards/wingify/jobs?content=true&pay_transparency=true"
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"wingify", api},
+		{"https://boards.greenhouse.io/wingify", api},
+		{"https://job-boards.greenhouse.io/wingify/jobs/5012238004", api},
+		{"job-boards.greenhouse.io/wingify", api},
+		{"https://boards.greenhouse.io/embed/job_board?for=wingify", api},
+		{"https://boards-api.greenhouse.io/v1/boards/wingify/jobs", api},
+		{"https://BOARDS.GREENHOUSE.IO:443/wingify", api},
+		{"https://boards.evilgreenhouse.io/wingify", ""},
+		{"https://greenhouse.io.evil.test/wingify", ""},
+		{"https://example.com/wingify", ""},
+		{"https://boards.greenhouse.io/wing ify", ""},
+	}
+	for _, tt := range tests {
+		got, err := Scraper{}.BoardURL(tt.ref)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("BoardURL(%q) = %q, want an error", tt.ref, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("BoardURL(%q) = %q, %v; want %q", tt.ref, got, err, tt.want)
+		}
+	}
+}
+
+func TestImportBoard(t *testing.T) {
+	client := boardServer(t)
+	jobs, err := scraper.ImportBoard(context.Background(), client, "greenhouse", "https://job-boards.greenhouse.io/wingify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(client.requested) != 1 || !strings.HasPrefix(client.requested[0], "https://boards-api.greenhouse.io/v1/boards/wingify/jobs?") {
+		t.Errorf("requested %v", client.requested)
+	}
+
+	want := []scraper.Job{
+		{ID: "5012238004", Role: "Senior Backend Engineer (Go)", Company: "Wingify", Salary: "INR 3,600,000 - 5,400,000", URL: "https://job-boards.greenhouse.io/wingify/jobs/5012238004"},
+		{ID: "5019120004", Role: "Product Design Intern", Company: "Wingify", URL: "https://job-boards.greenhouse.io/wingify/jobs/5019120004"},
+		{ID: "4988700004", Role: "Technical Writer", Company: "Wingify", URL: "https://job-boards.greenhouse.io/wingify/jobs/4988700004"},
+	}
+	if len(jobs) != len(want) {
+		t.Fatalf("got %d jobs, want %d", len(jobs), len(want))
+	}
+	for i, w := range want {
+		got := jobs[i]
+		if got.Platform != "greenhouse" || got.ID != w.ID || got.Role != w.Role || got.Company != w.Company || got.Salary != w.Salary || got.URL != w.URL {
+			t.Errorf("job %d = %q %q %q %q %q, want %q %q %q %q %q", i,
+				got.ID, got.Role, got.Company, got.Salary, got.URL, w.ID, w.Role, w.Company, w.Salary, w.URL)
+		}
+	}
+}
+
+func TestImportBoardNotFound(t *testing.T) {
+	client := boardServer(t)
+	_, err := scraper.ImportBoard(context.Background(), client, "greenhouse", "nosuchboard")
+	if err == nil || !strings.Contains(err.Error(), "no greenhouse board") {
+		t.Errorf("ImportBoard of a missing board = %v, want a not found error", err)
+	}
+}
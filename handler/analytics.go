@@ -43,11 +43,18 @@ func GetAnalytics(db *gorm.DB) gin.HandlerFunc {
 			return
 		}
 
+		engagement, err := database.GetEngagementStats(db, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get engagement data"})
+			return
+		}
+
 		c.JSON(http.StatusOK, gin.H{
 			"total_applications":  analytics.TotalApplications,
 			"cold_emails_sent":    analytics.ColdEmailsSent,
 			"platform_breakdown":  analytics.PlatformBreakdown,
 			"monthly_stats":       analytics.MonthlyStats,
+			"engagement":          engagement,
 		})
 	}
 }
@@ -2,14 +2,14 @@ package handler
 
 import (
 	"aiapply/database"
-	"aiapply/emailer"
+	"aiapply/mailqueue"
 	"aiapply/models"
 	"aiapply/utils"
 	"fmt"
 	"log"
 	"net/http"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -32,6 +32,17 @@ func CreateApplication(db *gorm.DB) gin.HandlerFunc {
 		}
 		application.UserID = userID
 
+		if msg := checkReferences(db, userID, application.TemplateID, application.SequenceID); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+			return
+		}
+		if application.JobID != nil {
+			if err := fillFromJob(db, &application); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Job not found"})
+				return
+			}
+		}
+
 		// Perform core application creation in a transaction
 		err = db.Transaction(func(tx *gorm.DB) error {
 			if err := tx.Create(&application).Error; err != nil {
@@ -42,11 +53,14 @@ func CreateApplication(db *gorm.DB) gin.HandlerFunc {
 				coldEmail := models.ColdEmail{
 					UserID:        application.UserID,
 					ApplicationID: application.ID,
-					Status:        "sent",
+					Status:        "queued",
 				}
 				if err := tx.Create(&coldEmail).Error; err != nil {
 					return err
 				}
+				if err := mailqueue.Enqueue(tx, application); err != nil {
+					return err
+				}
 			}
 			return nil
 		})
@@ -57,47 +71,8 @@ func CreateApplication(db *gorm.DB) gin.HandlerFunc {
 			return
 		}
 
-		// If cold email, send emails in a goroutine
-		if application.ApplicationType == "cold_email" {
-			var wg sync.WaitGroup
-			for _, name := range application.EmployeeNames {
-				wg.Add(1)
-				go func(name string, app models.JobApplication) {
-					defer wg.Done()
-					var user models.User
-					if err := db.First(&user, app.UserID).Error; err != nil {
-						log.Printf("Failed to fetch user for cold email: %v", err)
-						return
-					}
-
-					parts := strings.Fields(name)
-					if len(parts) < 2 {
-						log.Printf("Skipping invalid name: %s", name)
-						return
-					}
-					firstName, lastName := parts[0], parts[len(parts)-1]
-					permutations := emailer.GeneratePermutations(firstName, lastName, app.Domain)
-
-					for _, email := range permutations {
-						if err := emailer.ValidateEmail(email); err == nil {
-							log.Printf("Sending cold email to %s for job %s", email, app.JobTitle)
-
-							if err := emailer.SendApplicationEmail(email, app.JobTitle, user); err == nil {
-								// Update analytics only after email is successfully sent
-								if err := database.UpdateAnalytics(db, int(app.UserID), app.Platform, true); err != nil {
-									log.Printf("Failed to update analytics for %s: %v", email, err)
-								}
-								break // Assuming one valid email per name is enough
-							}
-						}
-					}
-				}(name, application)
-			}
-			go func() {
-				wg.Wait()
-				log.Println("All cold emails processed.")
-			}()
-		} else {
+		// Cold emails are sent by the mail queue workers
+		if application.ApplicationType != "cold_email" {
 			// Update analytics for other application types
 			go func() {
 				if err := database.UpdateAnalytics(db, int(application.UserID), application.Platform, false); err != nil {
@@ -129,11 +104,23 @@ func GetApplications(db *gorm.DB) gin.HandlerFunc {
 	}
 }
 
+// applicationUpdate holds the fields of an application its owner may change.
+// Omitted fields are left as they are
+type applicationUpdate struct {
+	JobTitle    *string    `json:"job_title"`
+	CompanyName *string    `json:"company_name"`
+	Platform    *string    `json:"platform"`
+	DateApplied *time.Time `json:"date_applied"`
+	Status      *string    `json:"status"`
+	TemplateID  *uint      `json:"template_id"`
+	SequenceID  *uint      `json:"sequence_id"`
+}
+
 // UpdateApplication updates an existing application
 func UpdateApplication(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var application models.JobApplication
-		if err := c.ShouldBindJSON(&application); err != nil {
+		var req applicationUpdate
+		if err := c.ShouldBindJSON(&req); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -150,15 +137,105 @@ func UpdateApplication(db *gorm.DB) gin.HandlerFunc {
 			return
 		}
 
-		if err := db.Model(&existingApplication).Updates(application).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update application"})
+		if msg := checkReferences(db, userID, req.TemplateID, req.SequenceID); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": msg})
 			return
 		}
 
+		updates := make(map[string]any)
+		if req.JobTitle != nil {
+			updates["job_title"] = *req.JobTitle
+		}
+		if req.CompanyName != nil {
+			updates["company_name"] = *req.CompanyName
+		}
+		if req.Platform != nil {
+			updates["platform"] = *req.Platform
+		}
+		if req.DateApplied != nil {
+			updates["date_applied"] = *req.DateApplied
+		}
+		if req.Status != nil {
+			updates["status"] = *req.Status
+		}
+		if req.TemplateID != nil {
+			updates["template_id"] = *req.TemplateID
+		}
+		if req.SequenceID != nil {
+			updates["sequence_id"] = *req.SequenceID
+		}
+
+		previousStatus := existingApplication.Status
+		if len(updates) > 0 {
+			if err := db.Model(&existingApplication).Updates(updates).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update application"})
+				return
+			}
+		}
+
+		// Any progress on the application makes pending follow-ups obsolete
+		if req.Status != nil && *req.Status != "" && *req.Status != previousStatus {
+			if err := mailqueue.CancelFollowUps(db, existingApplication.ID, "application status changed to "+*req.Status); err != nil {
+				log.Printf("Failed to cancel follow-ups for application %d: %v", existingApplication.ID, err)
+			}
+		}
+
 		c.JSON(http.StatusOK, existingApplication)
 	}
 }
 
+// checkReferences makes sure the template and sequence an application points
+// at belong to the user. It returns the error to report, or "" when both do
+func checkReferences(db *gorm.DB, userID uint, templateID, sequenceID *uint) string {
+	if templateID != nil {
+		if _, err := database.GetTemplate(db, userID, *templateID); err != nil {
+			return "Template not found"
+		}
+	}
+	if sequenceID != nil {
+		var count int64
+		if err := db.Model(&models.Sequence{}).Where("id = ? AND user_id = ?", *sequenceID, userID).Count(&count).Error; err != nil || count == 0 {
+			return "Sequence not found"
+		}
+	}
+	return ""
+}
+
+// fillFromJob completes an application from the scraped job it names. The
+// hiring team a job page lists becomes the people to cold email, unless the
+// request names them itself
+func fillFromJob(db *gorm.DB, application *models.JobApplication) error {
+	var job models.Job
+	if err := db.First(&job, *application.JobID).Error; err != nil {
+		return err
+	}
+	if application.JobTitle == "" {
+		application.JobTitle = job.Role
+	}
+	if application.CompanyName == "" {
+		application.CompanyName = job.Company
+	}
+	if application.Platform == "" {
+		application.Platform = job.Platform
+	}
+	if len(application.EmployeeNames) == 0 {
+		application.EmployeeNames = hiringTeam(job)
+	}
+	return nil
+}
+
+// hiringTeam returns the names kept comma separated in a job's "hiring_team"
+// extra
+func hiringTeam(job models.Job) []string {
+	var names []string
+	for _, name := range strings.Split(job.Extras["hiring_team"], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // DeleteApplication deletes an application
 func DeleteApplication(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -199,3 +276,61 @@ func GetApplicationByID(db *gorm.DB) gin.HandlerFunc {
 		c.JSON(http.StatusOK, application)
 	}
 }
+
+// GetApplicationJobs returns the outbound email jobs queued for an application
+func GetApplicationJobs(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := utils.GetUserIDFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
+		}
+
+		var jobs []models.EmailJob
+		if err := db.Where("application_id = ? AND user_id = ?", c.Param("id"), userID).Order("id").Find(&jobs).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching email jobs"})
+			return
+		}
+
+		c.JSON(http.StatusOK, jobs)
+	}
+}
+
+// GetApplicationReplies returns the replies received to an application's cold emails
+func GetApplicationReplies(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := utils.GetUserIDFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
+		}
+
+		var replies []models.EmailReply
+		if err := db.Where("application_id = ? AND user_id = ?", c.Param("id"), userID).Order("received_at").Find(&replies).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching replies"})
+			return
+		}
+
+		c.JSON(http.StatusOK, replies)
+	}
+}
+
+// GetApplicationEmails returns every address generated for an application's
+// cold emails with its verification and send outcome
+func GetApplicationEmails(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := utils.GetUserIDFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
+		}
+
+		var candidates []models.EmailCandidate
+		if err := db.Where("application_id = ? AND user_id = ?", c.Param("id"), userID).Order("email_job_id, id").Find(&candidates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching emails"})
+			return
+		}
+
+		c.JSON(http.StatusOK, candidates)
+	}
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"aiapply/database/dbtest"
+	"aiapply/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func uploadPage(t *testing.T, db *gorm.DB, platform, file string) {
+	page, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("html", "page.html")
+	part.Write(page)
+	form.Close()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/scrape/:platform", ScrapeJobs(db))
+	req := httptest.NewRequest(http.MethodPost, "/scrape/"+platform, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("upload to %s answered %d: %s", platform, rec.Code, rec.Body)
+	}
+}
+
+func TestColdEmailHiringTeamOfScrapedJob(t *testing.T) {
+	db := dbtest.Open(t, &models.Job{}, &models.ScrapeRun{}, &models.JobApplication{}, &models.ColdEmail{}, &models.EmailJob{})
+
+	// the search page first, then the job page it has open
+	uploadPage(t, db, "linkedin", "../linkedin/network.html")
+	var searched models.Job
+	if err := db.Where("platform = ? AND external_id = ?", "linkedin", "4278494082").First(&searched).Error; err != nil {
+		t.Fatal(err)
+	}
+	uploadPage(t, db, "linkedin-job", "../linkedin/network.html")
+
+	var job models.Job
+	if err := db.First(&job, searched.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if job.Company != searched.Company || job.Location != searched.Location || job.URL != searched.URL {
+		t.Errorf("detail upload changed the search data: %+v, was %+v", job, searched)
+	}
+	if job.Extras["hiring_team"] != "Vaishali S." || job.Extras["description"] == "" {
+		t.Errorf("extras = %v, want the details", job.Extras)
+	}
+
+	r := gin.New()
+	r.POST("/applications", func(c *gin.Context) { c.Set("userID", "1") }, CreateApplication(db))
+	body, _ := json.Marshal(map[string]any{"application_type": "cold_email", "job_id": job.ID})
+	req := httptest.NewRequest(http.MethodPost, "/applications", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("create answered %d: %s", rec.Code, rec.Body)
+	}
+
+	var application models.JobApplication
+	if err := db.First(&application).Error; err != nil {
+		t.Fatal(err)
+	}
+	if application.JobTitle != job.Role || application.CompanyName != job.Company || application.Platform != "linkedin" {
+		t.Errorf("application = %+v, want the title and company of job %d", application, job.ID)
+	}
+	var queued []models.EmailJob
+	db.Where("application_id = ?", application.ID).Find(&queued)
+	if len(queued) != 1 || queued[0].EmployeeName != "Vaishali S." {
+		t.Errorf("queued %+v, want one email to Vaishali S.", queued)
+	}
+}
+
+func TestHiringTeam(t *testing.T) {
+	job := models.Job{Extras: map[string]string{"hiring_team": "Ananya Rao, Karthik Menon,  "}}
+	if got := hiringTeam(job); len(got) != 2 || got[0] != "Ananya Rao" || got[1] != "Karthik Menon" {
+		t.Errorf("hiringTeam = %q", got)
+	}
+	if got := hiringTeam(models.Job{}); got != nil {
+		t.Errorf("hiringTeam of a job without one = %q", got)
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"aiapply/database"
+	"aiapply/scraper"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type importRequest struct {
+	// URL is a board page, API link or bare board name
+	URL string `json:"url" binding:"required"`
+}
+
+// ImportBoard downloads the JSON listing of a Greenhouse or Lever board and
+// stores its jobs like ScrapeJobs. Saved listings can be uploaded to
+// ScrapeJobs instead
+func ImportBoard(db *gorm.DB, client scraper.HTTPClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		platform := c.Param("platform")
+
+		var req importRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		s, ok := scraper.Get(platform)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Platform '%s' not supported", platform)})
+			return
+		}
+		importer, ok := s.(scraper.BoardImporter)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Platform '%s' has no job boards to import", platform)})
+			return
+		}
+		if _, err := importer.BoardURL(req.URL); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		jobs, err := scraper.ImportBoard(c.Request.Context(), client, platform, req.URL)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("Failed to import jobs: %v", err)})
+			return
+		}
+
+		saveJobs(c, db, platform, "import", -1, jobs)
+	}
+}
+
+// linkWellfound points ATS jobs at the Wellfound page of their company when
+// a stored Wellfound job has the same company name. Without a match, or
+// when the lookup fails, jobs are stored as they are
+func linkWellfound(db *gorm.DB, jobs []scraper.Job) {
+	companies, err := database.PlatformCompanies(db, "wellfound")
+	if err != nil {
+		log.Printf("Failed to look up Wellfound companies: %v", err)
+		return
+	}
+	pages := make(map[string]database.CompanyPage, len(companies))
+	for _, company := range companies {
+		pages[scraper.CompanyKey(company.Company)] = company
+	}
+
+	for i := range jobs {
+		page, ok := pages[scraper.CompanyKey(jobs[i].Company)]
+		if !ok {
+			continue
+		}
+		jobs[i].SetExtra("wellfound_company", page.Company)
+		jobs[i].SetExtra("wellfound_url", page.URL)
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"aiapply/database/dbtest"
+	"aiapply/models"
+	"aiapply/scraper"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// stubClient answers every request from a local server, keeping the path.
+type stubClient struct {
+	server    *httptest.Server
+	requested []string
+}
+
+func (c *stubClient) Do(req *http.Request) (*http.Response, error) {
+	c.requested = append(c.requested, req.URL.String())
+	local := req.Clone(req.Context())
+	local.URL.Scheme = "http"
+	local.URL.Host = strings.TrimPrefix(c.server.URL, "http://")
+	return c.server.Client().Do(local)
+}
+
+func greenhouseStub(t *testing.T) *stubClient {
+	board, err := os.ReadFile("../greenhouse/greenhouse.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(board)
+	}))
+	t.Cleanup(server.Close)
+	return &stubClient{server: server}
+}
+
+func importBoard(db *gorm.DB, client scraper.HTTPClient, platform, ref string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/scrape/:platform/import", ImportBoard(db, client))
+	body, _ := json.Marshal(importRequest{URL: ref})
+	req := httptest.NewRequest(http.MethodPost, "/scrape/"+platform+"/import", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestImportBoardRejectsOtherHosts(t *testing.T) {
+	client := greenhouseStub(t)
+	for _, ref := range []string{"https://jobs.notlever.co/acme", "https://api.evillever.co/v0/postings/acme"} {
+		rec := importBoard(nil, client, "lever", ref)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("import of %s answered %d, want 400", ref, rec.Code)
+		}
+	}
+	if len(client.requested) != 0 {
+		t.Errorf("requested %v, want nothing", client.requested)
+	}
+}
+
+func TestImportBoardLinksWellfound(t *testing.T) {
+	db := dbtest.Open(t, &models.Job{}, &models.ScrapeRun{})
+	wellfound := models.Job{
+		Platform: "wellfound", ExternalID: "wf-1", Role: "Backend Engineer", Company: "Wingify",
+		Extras: map[string]string{"company_url": "https://wellfound.com/company/wingify"},
+	}
+	if err := db.Create(&wellfound).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	rec := importBoard(db, greenhouseStub(t), "greenhouse", "wingify")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("import answered %d: %s", rec.Code, rec.Body)
+	}
+	var resp struct {
+		Jobs []scraper.Job `json:"jobs"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Jobs) != 3 {
+		t.Fatalf("imported %d jobs, want 3", len(resp.Jobs))
+	}
+	for _, job := range resp.Jobs {
+		if job.Extras["wellfound_url"] != "https://wellfound.com/company/wingify" || job.Extras["wellfound_company"] != "Wingify" {
+			t.Errorf("job %s extras = %v, want the Wellfound page of Wingify", job.ID, job.Extras)
+		}
+	}
+
+	var stored models.Job
+	if err := db.Where("platform = ? AND external_id = ?", "greenhouse", "5012238004").First(&stored).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Extras["wellfound_url"] != "https://wellfound.com/company/wingify" {
+		t.Errorf("stored extras = %v, want the Wellfound link", stored.Extras)
+	}
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"aiapply/database"
+	"aiapply/scraper"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultJobsLimit = 50
+	maxJobsLimit     = 200
+)
+
+// ListJobs returns stored scraped jobs. Supported query parameters are
+// platform, company, location, skill, since and until (YYYY-MM-DD or
+// RFC 3339, matched against the posted date or else the first sighting),
+// open=true to leave out jobs past their apply-by date, city (aliases such
+// as Bangalore resolve to the canonical name, metros such as Delhi NCR match
+// their cities), country, policy (remote, hybrid or onsite),
+// remote_or_city=true to add remote jobs to a city search, currency, period
+// and min_salary, sort=salary, limit and offset
+func ListJobs(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := database.JobFilter{
+			Platform: c.Query("platform"),
+			Company:  c.Query("company"),
+			Location: c.Query("location"),
+			Skill:    c.Query("skill"),
+			Currency: strings.ToUpper(c.Query("currency")),
+			Period:   c.Query("period"),
+			Country:  c.Query("country"),
+			Limit:    defaultJobsLimit,
+		}
+		filter.SortBySalary = c.Query("sort") == "salary"
+		filter.RemoteOrCity = c.Query("remote_or_city") == "true"
+		filter.Open = c.Query("open") == "true"
+		if v := c.Query("city"); v != "" {
+			filter.City = v
+			if city, metro, ok := scraper.ResolveCity(v); ok {
+				filter.City = city
+				if city == "" {
+					filter.City = metro
+				}
+			}
+		}
+		if v := c.Query("policy"); v != "" {
+			policy := scraper.ParsePolicy(v)
+			if policy == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid policy"})
+				return
+			}
+			filter.RemotePolicy = policy
+		}
+
+		if v := c.Query("since"); v != "" {
+			since, _, err := parseDateParam(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid since date"})
+				return
+			}
+			filter.Since = &since
+		}
+		if v := c.Query("until"); v != "" {
+			until, dateOnly, err := parseDateParam(v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid until date"})
+				return
+			}
+			// a bare date includes the whole day
+			if dateOnly {
+				until = until.AddDate(0, 0, 1)
+			}
+			filter.Until = &until
+		}
+		if v := c.Query("min_salary"); v != "" {
+			minSalary, err := strconv.ParseFloat(v, 64)
+			if err != nil || minSalary < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid min_salary"})
+				return
+			}
+			filter.MinSalary = minSalary
+		}
+		if v := c.Query("limit"); v != "" {
+			limit, err := strconv.Atoi(v)
+			if err != nil || limit <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+				return
+			}
+			if limit > maxJobsLimit {
+				limit = maxJobsLimit
+			}
+			filter.Limit = limit
+		}
+		if v := c.Query("offset"); v != "" {
+			offset, err := strconv.Atoi(v)
+			if err != nil || offset < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+				return
+			}
+			filter.Offset = offset
+		}
+
+		jobs, err := database.ListJobs(db, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching jobs"})
+			return
+		}
+
+		c.JSON(http.StatusOK, jobs)
+	}
+}
+
+// parseDateParam accepts a date or a full timestamp and reports which it got
+func parseDateParam(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
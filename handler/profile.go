@@ -1,10 +1,15 @@
 package handler
 
 import (
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
 
+	"aiapply/database"
+	"aiapply/emailer"
 	"aiapply/models"
+	"aiapply/utils"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -70,3 +75,146 @@ func UpdateProfile(db *gorm.DB) gin.HandlerFunc {
 		c.JSON(http.StatusOK, user)
 	}
 }
+
+// mailSettingsRequest is the body accepted by UpdateMailSettings. Password is
+// only ever written, never returned.
+type mailSettingsRequest struct {
+	Host        string `json:"host" binding:"required"`
+	Port        int    `json:"port" binding:"required"`
+	FromAddress string `json:"from_address" binding:"required,email"`
+	FromName    string `json:"from_name"`
+	Username    string `json:"username"`
+	Password    string `json:"password"`
+	AuthMethod  string `json:"auth_method"`
+	IMAPHost    string `json:"imap_host"`
+	IMAPPort    int    `json:"imap_port"`
+	// IMAPAllowPlaintext must be set to log in to an IMAP server without TLS
+	IMAPAllowPlaintext bool `json:"imap_allow_plaintext"`
+}
+
+// GetMailSettings returns the outgoing mail server of the authenticated user
+func GetMailSettings(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := utils.GetUserIDFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
+		}
+
+		settings, err := database.GetMailSettings(db, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Mail settings not configured"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching mail settings"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"settings":     settings,
+			"has_password": settings.Password != "",
+		})
+	}
+}
+
+// UpdateMailSettings stores the outgoing mail server of the authenticated user.
+// An empty password keeps the one already stored.
+func UpdateMailSettings(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := utils.GetUserIDFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
+		}
+
+		var req mailSettingsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		switch req.AuthMethod {
+		case "", "plain", "login", "cram-md5", "none":
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "auth_method must be one of plain, login, cram-md5, none"})
+			return
+		}
+
+		settings := models.MailSettings{
+			UserID:      userID,
+			Host:        req.Host,
+			Port:        req.Port,
+			FromAddress: req.FromAddress,
+			FromName:    req.FromName,
+			Username:    req.Username,
+			AuthMethod:  req.AuthMethod,
+			IMAPHost:    req.IMAPHost,
+			IMAPPort:    req.IMAPPort,
+
+			IMAPAllowPlaintext: req.IMAPAllowPlaintext,
+		}
+
+		if req.Password != "" {
+			encrypted, err := utils.EncryptSecret(req.Password)
+			if err != nil {
+				log.Printf("Failed to encrypt mail password: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store credentials"})
+				return
+			}
+			settings.Password = encrypted
+		}
+		if existing, err := database.GetMailSettings(db, userID); err == nil {
+			if settings.Password == "" {
+				settings.Password = existing.Password
+			}
+			// Keep the reply polling position unless the inbox changed
+			if existing.IMAPHost == settings.IMAPHost && existing.Username == settings.Username {
+				settings.IMAPUIDValidity = existing.IMAPUIDValidity
+				settings.IMAPLastUID = existing.IMAPLastUID
+			}
+		}
+
+		if err := database.SaveMailSettings(db, &settings); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving mail settings"})
+			return
+		}
+
+		c.JSON(http.StatusOK, settings)
+	}
+}
+
+// TestMailSettings logs in to the configured mail server and sends a short
+// test message to the user's own address
+func TestMailSettings(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := utils.GetUserIDFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
+		}
+
+		settings, err := database.GetMailSettings(db, userID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Mail settings not configured"})
+			return
+		}
+
+		sender, err := emailer.NewSMTPSender(*settings)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if err := sender.Test(); err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := emailer.SendGenericEmail(db, userID, settings.FromAddress, "Mail settings test", "Your mail settings work. Cold emails will be sent from this address."); err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Test email sent to " + settings.FromAddress})
+	}
+}
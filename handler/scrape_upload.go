@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"aiapply/scraper"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	maxUploadFiles     = 50        // files per request, counting zip entries
+	maxUploadFileSize  = 20 << 20  // bytes per file or zip entry
+	maxUploadTotalSize = 100 << 20 // bytes per request, after unzipping
+)
+
+var (
+	errFileTooLarge   = fmt.Errorf("file is larger than %d MB", maxUploadFileSize>>20)
+	errUploadTooLarge = fmt.Errorf("uploaded files are larger than %d MB in total", maxUploadTotalSize>>20)
+	errTooManyFiles   = fmt.Errorf("at most %d files can be scraped at once", maxUploadFiles)
+)
+
+// uploadBudget counts the files and bytes of a request as they are read, so
+// that reading stops as soon as a limit is passed rather than after
+// everything is in memory
+type uploadBudget struct {
+	files int
+	bytes int64
+}
+
+func (b *uploadBudget) addFile() error {
+	b.files++
+	if b.files > maxUploadFiles {
+		return errTooManyFiles
+	}
+	return nil
+}
+
+// read reads one file, failing with errFileTooLarge when the file is over
+// maxUploadFileSize and errUploadTooLarge when the request is over
+// maxUploadTotalSize
+func (b *uploadBudget) read(r io.Reader) ([]byte, error) {
+	limit := min(int64(maxUploadFileSize), maxUploadTotalSize-b.bytes)
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	b.bytes += int64(len(data))
+	switch {
+	case b.bytes > maxUploadTotalSize:
+		return nil, errUploadTooLarge
+	case len(data) > maxUploadFileSize:
+		return nil, errFileTooLarge
+	case err != nil:
+		return nil, err
+	}
+	return data, nil
+}
+
+// fatal reports whether an upload error fails the whole request rather than
+// just the file
+func fatal(err error) bool {
+	return errors.Is(err, errUploadTooLarge) || errors.Is(err, errTooManyFiles)
+}
+
+type upload struct {
+	name string
+	data []byte
+	err  error
+}
+
+type uploadResult struct {
+	File        string               `json:"file"`
+	Platform    string               `json:"platform,omitempty"`
+	Detection   *scraper.Detection   `json:"detection,omitempty"`
+	Jobs        []scraper.Job        `json:"jobs"`
+	Diagnostics *scraper.Diagnostics `json:"diagnostics,omitempty"`
+	Error       string               `json:"error,omitempty"`
+}
+
+// ScrapeUploads works out the platform of every uploaded page or API export
+// and scrapes it. Zip archives are opened and each entry handled as a file
+// of its own. A failing file does not stop the others; its error is part of
+// its result.
+func ScrapeUploads(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// zips expand, so the budget is checked again while reading
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadTotalSize+1<<20)
+		form, err := c.MultipartForm()
+		var maxBytes *http.MaxBytesError
+		if errors.As(err, &maxBytes) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": errUploadTooLarge.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Multipart form with HTML, JSON or zip files is required"})
+			return
+		}
+
+		// form fields come as a map; sort them so results keep an order
+		fields := make([]string, 0, len(form.File))
+		count := 0
+		for field, headers := range form.File {
+			fields = append(fields, field)
+			count += len(headers)
+		}
+		sort.Strings(fields)
+		if count > maxUploadFiles {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errTooManyFiles.Error()})
+			return
+		}
+
+		var uploads []upload
+		var budget uploadBudget
+		for _, field := range fields {
+			for _, header := range form.File[field] {
+				entries, err := readUpload(header, &budget)
+				if fatal(err) {
+					status := http.StatusRequestEntityTooLarge
+					if errors.Is(err, errTooManyFiles) {
+						status = http.StatusBadRequest
+					}
+					c.JSON(status, gin.H{"error": err.Error()})
+					return
+				}
+				uploads = append(uploads, entries...)
+			}
+		}
+
+		if len(uploads) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "At least one HTML, JSON or zip file is required"})
+			return
+		}
+
+		results := make([]uploadResult, 0, len(uploads))
+		for _, u := range uploads {
+			results = append(results, scrapeUpload(db, u))
+		}
+		c.JSON(http.StatusOK, gin.H{"results": results})
+	}
+}
+
+// readUpload reads one uploaded file, or the entries of an uploaded zip. Only
+// errors that fail the whole request are returned; the others are kept with
+// the file they belong to
+func readUpload(header *multipart.FileHeader, budget *uploadBudget) ([]upload, error) {
+	u := upload{name: header.Filename}
+	if err := budget.addFile(); err != nil {
+		return nil, err
+	}
+	if header.Size > maxUploadFileSize {
+		u.err = errFileTooLarge
+		return []upload{u}, nil
+	}
+	file, err := header.Open()
+	if err != nil {
+		u.err = fmt.Errorf("could not read uploaded file")
+		return []upload{u}, nil
+	}
+	u.data, err = budget.read(file)
+	file.Close()
+	switch {
+	case fatal(err):
+		return nil, err
+	case errors.Is(err, errFileTooLarge):
+		u.err = err
+	case err != nil:
+		u.err = fmt.Errorf("could not read uploaded file")
+	}
+
+	if u.err == nil && strings.EqualFold(path.Ext(u.name), ".zip") {
+		// the archive itself is not scraped, so its entries take its place
+		budget.files--
+		entries, err := unzipUploads(u.name, u.data, budget)
+		if fatal(err) {
+			return nil, err
+		}
+		if err == nil {
+			return entries, nil
+		}
+		budget.files++
+		u.err = err
+	}
+	return []upload{u}, nil
+}
+
+// scrapeUpload detects the platform of one uploaded file and stores its jobs
+func scrapeUpload(db *gorm.DB, u upload) uploadResult {
+	result := uploadResult{File: u.name, Jobs: []scraper.Job{}}
+	if u.err != nil {
+		result.Error = u.err.Error()
+		return result
+	}
+
+	detected, err := scraper.Detect(u.data)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Platform = detected.Platform
+	result.Detection = &detected
+
+	s, _ := scraper.Get(detected.Platform)
+	jobs, diag, _, err := scrapeData(db, s, detected.Platform, u.data)
+	result.Diagnostics = &diag
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Jobs = jobs
+	return result
+}
+
+// unzipUploads returns the files of a zip archive, skipping folders, hidden
+// files and the resource forks macOS adds. Entries are read no further than
+// maxUploadFileSize, whatever size their header claims, and count against
+// the request's budget as they are read.
+func unzipUploads(name string, data []byte, budget *uploadBudget) ([]upload, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("not a valid zip archive")
+	}
+
+	var uploads []upload
+	for _, f := range archive.File {
+		base := path.Base(f.Name)
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") || strings.HasPrefix(base, ".") {
+			continue
+		}
+		if err := budget.addFile(); err != nil {
+			return nil, err
+		}
+
+		u := upload{name: name + "/" + f.Name}
+		if f.UncompressedSize64 > maxUploadFileSize {
+			u.err = errFileTooLarge
+			uploads = append(uploads, u)
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			u.err = fmt.Errorf("could not read file from archive")
+			uploads = append(uploads, u)
+			continue
+		}
+		u.data, err = budget.read(rc)
+		rc.Close()
+		switch {
+		case fatal(err):
+			return nil, err
+		case errors.Is(err, errFileTooLarge):
+			u.err = err
+		case err != nil:
+			u.err = fmt.Errorf("could not read file from archive")
+		}
+		uploads = append(uploads, u)
+	}
+	return uploads, nil
+}
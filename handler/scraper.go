@@ -1,14 +1,29 @@
 package handler
 
 import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
-	"aiapply/cuvette"
-	"aiapply/linkedin"
-	"aiapply/wellfound"
+	_ "aiapply/cuvette"
+	"aiapply/database"
+	_ "aiapply/greenhouse"
+	_ "aiapply/indeed"
+	_ "aiapply/internshala"
+	_ "aiapply/lever"
+	_ "aiapply/linkedin"
+	"aiapply/models"
+	_ "aiapply/naukri"
+	_ "aiapply/schemaorg"
+	"aiapply/scraper"
+	_ "aiapply/wellfound"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -18,39 +33,259 @@ func ScrapeJobs(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		platform := c.Param("platform")
 
+		s, ok := scraper.Get(platform)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Platform '%s' not supported", platform)})
+			return
+		}
+
 		file, _, err := c.Request.FormFile("html")
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "HTML file is required"})
+			// JSON exports such as Cuvette's API responses
+			file, _, err = c.Request.FormFile("file")
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "HTML or JSON file is required"})
 			return
 		}
 		defer file.Close()
 
-		content, err := io.ReadAll(file)
+		data, err := io.ReadAll(file)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read uploaded file"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Could not read uploaded file"})
+			return
+		}
+
+		jobs, diag, status, err := scrapeData(db, s, platform, data)
+		if status == http.StatusUnprocessableEntity {
+			// most empty results come from a page of another platform
+			response := gin.H{"error": err.Error(), "diagnostics": diag}
+			if detected, derr := scraper.Detect(data); derr == nil && detected.Platform != platform {
+				response["error"] = fmt.Sprintf("This looks like a %s page, not %s; upload it to /api/scrape/%s or /api/scrape", detected.Platform, platform, detected.Platform)
+				response["detected"] = detected
+			}
+			c.JSON(status, response)
 			return
 		}
+		if err != nil {
+			c.JSON(status, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"jobs": jobs, "diagnostics": diag})
+	}
+}
+
+type fetchRequest struct {
+	URL   string `json:"url" binding:"required"`
+	Limit int    `json:"limit"`
+}
+
+const maxFetchLimit = 200
+
+// FetchJobs loads a search URL in a headless browser instead of taking an
+// uploaded page, then parses and stores the listings like ScrapeJobs
+func FetchJobs(db *gorm.DB, fetcher *scraper.Fetcher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		platform := c.Param("platform")
 
-		var jobs interface{}
-		var scrapeErr error
+		var req fetchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.Limit < 0 || req.Limit > maxFetchLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Limit must be at most %d", maxFetchLimit)})
+			return
+		}
 
-		switch platform {
-		case "linkedin":
-			jobs, scrapeErr = linkedin.Jobscrapper(strings.NewReader(string(content)))
-		case "wellfound":
-			jobs, scrapeErr = wellfound.ScrapeJobDetailsFromReader(strings.NewReader(string(content)))
-		case "cuvette":
-			jobs, scrapeErr = cuvette.ScrapeCuvetteListings(strings.NewReader(string(content)))
-		default:
+		s, ok := scraper.Get(platform)
+		if !ok {
 			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Platform '%s' not supported", platform)})
 			return
 		}
+		if _, ok := s.(scraper.Fetchable); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Platform '%s' cannot be fetched", platform)})
+			return
+		}
+		if err := fetcher.CheckURL(s, req.URL); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 
-		if scrapeErr != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to scrape jobs: %v", scrapeErr)})
+		jobs, err := fetcher.Fetch(c.Request.Context(), platform, req.URL, req.Limit)
+		if errors.Is(err, scraper.ErrFetcherBusy) {
+			c.Header("Retry-After", "30")
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil && len(jobs) == 0 {
+			c.JSON(http.StatusBadGateway, gin.H{"error": fmt.Sprintf("Failed to fetch jobs: %v", err)})
 			return
 		}
+		if err != nil {
+			// keep what the earlier pages gave
+			log.Printf("fetch %s %s stopped early: %v", platform, req.URL, err)
+		}
+
+		saveJobs(c, db, platform, "fetch", -1, jobs)
+	}
+}
 
-		c.JSON(http.StatusOK, jobs)
+// saveJobs stores jobs like storeJobs and responds with them
+func saveJobs(c *gin.Context, db *gorm.DB, platform, source string, cards int, jobs []scraper.Job) {
+	diag, status, err := storeJobs(db, platform, source, cards, jobs)
+	if err != nil {
+		c.JSON(status, gin.H{"error": err.Error(), "diagnostics": diag})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"jobs": jobs, "diagnostics": diag})
+}
+
+// scrapeData parses an uploaded page with s and stores the jobs. On error
+// it also returns the HTTP status to answer with
+func scrapeData(db *gorm.DB, s scraper.Scraper, platform string, data []byte) ([]scraper.Job, scraper.Diagnostics, int, error) {
+	jobs, err := s.Scrape(bytes.NewReader(data))
+	if err != nil {
+		diag := scraper.Diagnose(platform, -1, nil)
+		recordRun(db, diag, "upload", err)
+		return nil, diag, http.StatusInternalServerError, fmt.Errorf("Failed to scrape jobs: %v", err)
+	}
+
+	// counted apart from parsing to see the cards the parser skipped
+	cards := -1
+	if counter, ok := s.(scraper.CardCounter); ok {
+		if n, err := counter.CountCards(bytes.NewReader(data)); err == nil {
+			cards = n
+		}
+	}
+
+	diag, status, err := storeJobs(db, platform, "upload", cards, jobs)
+	return jobs, diag, status, err
+}
+
+// storeJobs normalizes, diagnoses and stores scraped jobs. A page without a
+// single job is refused, as it usually means the wrong page was given or the
+// platform changed its markup
+func storeJobs(db *gorm.DB, platform, source string, cards int, jobs []scraper.Job) (scraper.Diagnostics, int, error) {
+	// normalized first so the fill rates count the resolved dates
+	scraper.Normalize(jobs)
+	diag := scraper.Diagnose(platform, cards, jobs)
+	if previous, err := database.LastScrapeRun(db, platform); err == nil && previous != nil {
+		diag.CompareWith(previous.FillRates)
+	}
+	if len(diag.Warnings) > 0 {
+		log.Printf("scrape %s (%s): %s", platform, source, strings.Join(diag.Warnings, "; "))
+	}
+
+	if len(jobs) == 0 {
+		err := fmt.Errorf("no %s job listings found; check that this is a saved %s page, otherwise its markup may have changed since selectors version %d", platform, platform, diag.SelectorVersion)
+		recordRun(db, diag, source, err)
+		return diag, http.StatusUnprocessableEntity, err
+	}
+	recordRun(db, diag, source, nil)
+
+	if s, ok := scraper.Get(platform); ok {
+		if _, ok := s.(scraper.BoardImporter); ok {
+			linkWellfound(db, jobs)
+		}
+	}
+
+	if err := database.UpsertJobs(db, jobRecords(jobs)); err != nil {
+		return diag, http.StatusInternalServerError, fmt.Errorf("Failed to save scraped jobs")
+	}
+	return diag, http.StatusOK, nil
+}
+
+// recordRun keeps the diagnostics of a scrape; failing to do so only costs
+// history, so it is logged rather than returned
+func recordRun(db *gorm.DB, diag scraper.Diagnostics, source string, scrapeErr error) {
+	run := models.ScrapeRun{
+		Platform:        diag.Platform,
+		Source:          source,
+		SelectorVersion: diag.SelectorVersion,
+		Cards:           diag.Cards,
+		Jobs:            diag.Jobs,
+		FillRates:       diag.FillRates,
+		Warnings:        diag.Warnings,
+	}
+	if scrapeErr != nil {
+		run.Error = scrapeErr.Error()
+	}
+	if err := database.RecordScrapeRun(db, &run); err != nil {
+		log.Printf("could not record scrape run: %v", err)
+	}
+}
+
+// ListScrapeRuns returns recent scrape diagnostics, newest first. Supported
+// query parameters are platform and limit
+func ListScrapeRuns(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := defaultJobsLimit
+		if v := c.Query("limit"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+				return
+			}
+			limit = min(n, maxJobsLimit)
+		}
+
+		runs, err := database.ListScrapeRuns(db, c.Query("platform"), limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching scrape runs"})
+			return
+		}
+		c.JSON(http.StatusOK, runs)
+	}
+}
+
+// jobRecords converts scraped jobs into rows of the jobs table. Listings
+// without an ID of their own get one derived from what identifies them
+func jobRecords(jobs []scraper.Job) []models.Job {
+	records := make([]models.Job, 0, len(jobs))
+	for _, job := range jobs {
+		externalID := job.ID
+		if externalID == "" {
+			key := job.URL
+			if key == "" {
+				key = strings.ToLower(job.Role + "|" + job.Company + "|" + job.Location)
+			}
+			sum := sha1.Sum([]byte(key))
+			externalID = "h-" + hex.EncodeToString(sum[:8])
+		}
+		record := models.Job{
+			Platform:   job.Platform,
+			ExternalID: externalID,
+			Role:       job.Role,
+			Company:    job.Company,
+			Location:   job.Location,
+			Salary:     job.Salary,
+			URL:        job.URL,
+			Skills:     job.Skills,
+			PostedAt:   job.PostedAt,
+			ApplyBy:    job.ApplyBy,
+			Extras:     job.Extras,
+		}
+		if comp := job.Compensation; comp != nil {
+			if comp.Max > 0 {
+				record.SalaryMin, record.SalaryMax = &comp.Min, &comp.Max
+			}
+			if comp.EquityMax > 0 {
+				record.EquityMin, record.EquityMax = &comp.EquityMin, &comp.EquityMax
+			}
+			record.SalaryCurrency = comp.Currency
+			record.SalaryPeriod = comp.Period
+			record.Stipend = comp.Stipend
+		}
+		if place := job.Workplace; place != nil {
+			record.City = place.City
+			record.Region = place.Region
+			record.Country = place.Country
+			record.RemotePolicy = place.Policy
+			record.Cities = place.Cities()
+		}
+		records = append(records, record)
 	}
-}
\ No newline at end of file
+	return records
+}
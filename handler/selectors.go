@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"aiapply/scraper"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSelectors returns the scraper selectors in use and their version
+func GetSelectors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		config, source := scraper.CurrentSelectors()
+		c.JSON(http.StatusOK, gin.H{"source": source, "platforms": config.Platforms})
+	}
+}
+
+// ReloadSelectors rereads the selector file without waiting for the watcher
+func ReloadSelectors(path string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if path == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No selector file configured"})
+			return
+		}
+		source, err := scraper.LoadSelectorFile(path)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Failed to reload selectors: %v", err)})
+			return
+		}
+		c.JSON(http.StatusOK, source)
+	}
+}
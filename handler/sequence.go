@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"aiapply/models"
+	"aiapply/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// sequenceRequest is the body accepted when creating or updating a sequence
+type sequenceRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Steps []struct {
+		DelayDays  int   `json:"delay_days" binding:"required,min=1"`
+		TemplateID *uint `json:"template_id"`
+	} `json:"steps" binding:"required,min=1,dive"`
+}
+
+// ListSequences returns the follow-up sequences of the authenticated user
+func ListSequences(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := utils.GetUserIDFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
+		}
+
+		var sequences []models.Sequence
+		if err := db.Preload("Steps").Where("user_id = ?", userID).Order("id").Find(&sequences).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching sequences"})
+			return
+		}
+
+		c.JSON(http.StatusOK, sequences)
+	}
+}
+
+// GetSequence returns a single follow-up sequence with its steps
+func GetSequence(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		seq, ok := loadSequence(c, db)
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, seq)
+	}
+}
+
+// CreateSequence stores a new follow-up sequence
+func CreateSequence(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := utils.GetUserIDFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
+		}
+
+		var req sequenceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		seq := models.Sequence{UserID: userID, Name: req.Name}
+		steps, err := sequenceSteps(db, userID, req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		seq.Steps = steps
+
+		if err := db.Create(&seq).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create sequence"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, seq)
+	}
+}
+
+// UpdateSequence replaces the name and steps of a sequence. Follow-ups that
+// are already scheduled keep their original timing.
+func UpdateSequence(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		seq, ok := loadSequence(c, db)
+		if !ok {
+			return
+		}
+
+		var req sequenceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		steps, err := sequenceSteps(db, seq.UserID, req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("sequence_id = ?", seq.ID).Delete(&models.SequenceStep{}).Error; err != nil {
+				return err
+			}
+			seq.Name = req.Name
+			seq.Steps = steps
+			return tx.Save(seq).Error
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update sequence"})
+			return
+		}
+
+		c.JSON(http.StatusOK, seq)
+	}
+}
+
+// DeleteSequence deletes a follow-up sequence
+func DeleteSequence(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		seq, ok := loadSequence(c, db)
+		if !ok {
+			return
+		}
+
+		if err := db.Select("Steps").Delete(seq).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete sequence"})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// GetApplicationFollowUps returns the follow-ups scheduled for an application
+func GetApplicationFollowUps(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := utils.GetUserIDFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
+		}
+
+		var followUps []models.FollowUp
+		if err := db.Where("application_id = ? AND user_id = ?", c.Param("id"), userID).Order("due_at").Find(&followUps).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching follow-ups"})
+			return
+		}
+
+		c.JSON(http.StatusOK, followUps)
+	}
+}
+
+// sequenceSteps turns the request steps into models, checking that every
+// referenced template belongs to the user.
+func sequenceSteps(db *gorm.DB, userID uint, req sequenceRequest) ([]models.SequenceStep, error) {
+	steps := make([]models.SequenceStep, 0, len(req.Steps))
+	for i, s := range req.Steps {
+		if s.TemplateID != nil {
+			var count int64
+			if err := db.Model(&models.EmailTemplate{}).Where("id = ? AND user_id = ?", *s.TemplateID, userID).Count(&count).Error; err != nil {
+				return nil, err
+			}
+			if count == 0 {
+				return nil, errors.New("template not found")
+			}
+		}
+		steps = append(steps, models.SequenceStep{Position: i + 1, DelayDays: s.DelayDays, TemplateID: s.TemplateID})
+	}
+	return steps, nil
+}
+
+// loadSequence fetches the sequence named by the :id param for the
+// authenticated user, writing the error response itself when it fails.
+func loadSequence(c *gin.Context, db *gorm.DB) (*models.Sequence, bool) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		return nil, false
+	}
+
+	var seq models.Sequence
+	if err := db.Preload("Steps").Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&seq).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Sequence not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sequence"})
+		return nil, false
+	}
+	return &seq, true
+}
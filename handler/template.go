@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"aiapply/emailer"
+	"aiapply/models"
+	"aiapply/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// templateRequest is the body accepted when creating or updating a template
+type templateRequest struct {
+	Name      string `json:"name" binding:"required"`
+	Subject   string `json:"subject" binding:"required"`
+	PlainBody string `json:"plain_body" binding:"required"`
+	HTMLBody  string `json:"html_body" binding:"required"`
+}
+
+// ListTemplates returns the email templates of the authenticated user
+func ListTemplates(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := utils.GetUserIDFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
+		}
+
+		var templates []models.EmailTemplate
+		if err := db.Where("user_id = ?", userID).Order("id").Find(&templates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching templates"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"templates": templates,
+			"default":   emailer.DefaultTemplate(),
+		})
+	}
+}
+
+// GetTemplate returns a single email template
+func GetTemplate(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tmpl, ok := loadTemplate(c, db)
+		if !ok {
+			return
+		}
+		c.JSON(http.StatusOK, tmpl)
+	}
+}
+
+// CreateTemplate stores a new email template after checking that it parses
+func CreateTemplate(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := utils.GetUserIDFromContext(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			return
+		}
+
+		var req templateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		tmpl := models.EmailTemplate{
+			UserID:    userID,
+			Name:      req.Name,
+			Subject:   req.Subject,
+			PlainBody: req.PlainBody,
+			HTMLBody:  req.HTMLBody,
+		}
+		if err := emailer.ValidateTemplate(tmpl); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := db.Create(&tmpl).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create template"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, tmpl)
+	}
+}
+
+// UpdateTemplate replaces the content of an email template
+func UpdateTemplate(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tmpl, ok := loadTemplate(c, db)
+		if !ok {
+			return
+		}
+
+		var req templateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		tmpl.Name = req.Name
+		tmpl.Subject = req.Subject
+		tmpl.PlainBody = req.PlainBody
+		tmpl.HTMLBody = req.HTMLBody
+		if err := emailer.ValidateTemplate(*tmpl); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := db.Save(tmpl).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update template"})
+			return
+		}
+
+		c.JSON(http.StatusOK, tmpl)
+	}
+}
+
+// DeleteTemplate deletes an email template
+func DeleteTemplate(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tmpl, ok := loadTemplate(c, db)
+		if !ok {
+			return
+		}
+
+		if err := db.Delete(tmpl).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete template"})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// PreviewTemplate renders a template with the user's profile and sample recipient values
+func PreviewTemplate(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tmpl, ok := loadTemplate(c, db)
+		if !ok {
+			return
+		}
+
+		var user models.User
+		if err := db.First(&user, tmpl.UserID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+
+		data := emailer.NewTemplateData(user,
+			c.DefaultQuery("name", "Jane Doe"),
+			c.DefaultQuery("company", "Acme"),
+			c.DefaultQuery("role", "Software Engineer"))
+		subject, plain, html, err := emailer.RenderTemplate(*tmpl, data)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"subject": subject, "plain_body": plain, "html_body": html})
+	}
+}
+
+// loadTemplate fetches the template named by the :id param for the
+// authenticated user, writing the error response itself when it fails.
+func loadTemplate(c *gin.Context, db *gorm.DB) (*models.EmailTemplate, bool) {
+	userID, err := utils.GetUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		return nil, false
+	}
+
+	var tmpl models.EmailTemplate
+	if err := db.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&tmpl).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Template not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch template"})
+		return nil, false
+	}
+	return &tmpl, true
+}
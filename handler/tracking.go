@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"aiapply/database"
+	"aiapply/emailer"
+	"aiapply/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// transparentGIF is a 1x1 pixel returned by the open tracking endpoint
+var transparentGIF = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// TrackOpen records that a recipient loaded the tracking pixel of an email.
+// It always answers with the pixel so mail clients never show a broken image.
+func TrackOpen(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		recordEmailEvent(db, c, models.EmailEventOpen, "")
+
+		c.Header("Cache-Control", "no-store, no-cache, must-revalidate, private")
+		c.Data(http.StatusOK, "image/gif", transparentGIF)
+	}
+}
+
+// TrackClick records a click on a rewritten link and redirects to its target
+func TrackClick(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Param("token")
+		target := c.Query("u")
+		if target == "" || !emailer.ValidClickSignature(token, target, c.Query("sig")) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid link"})
+			return
+		}
+
+		recordEmailEvent(db, c, models.EmailEventClick, target)
+		c.Redirect(http.StatusFound, target)
+	}
+}
+
+// recordEmailEvent stores an open or click and moves the email and its
+// application forward. Unknown tokens are ignored.
+func recordEmailEvent(db *gorm.DB, c *gin.Context, eventType, url string) {
+	var job models.EmailJob
+	if err := db.Where("tracking_token = ?", c.Param("token")).First(&job).Error; err != nil {
+		return
+	}
+
+	event := models.EmailEvent{
+		EmailJobID:    job.ID,
+		UserID:        job.UserID,
+		ApplicationID: job.ApplicationID,
+		Type:          eventType,
+		URL:           url,
+		IP:            c.ClientIP(),
+		UserAgent:     c.Request.UserAgent(),
+	}
+	if err := db.Create(&event).Error; err != nil {
+		log.Printf("Failed to record %s event for email job %d: %v", eventType, job.ID, err)
+		return
+	}
+
+	// A click implies the email was opened, even if images were blocked
+	now := time.Now()
+	db.Model(&models.EmailJob{}).Where("id = ? AND opened_at IS NULL", job.ID).Update("opened_at", now)
+	status := "opened"
+	if eventType == models.EmailEventClick {
+		db.Model(&models.EmailJob{}).Where("id = ? AND clicked_at IS NULL", job.ID).Update("clicked_at", now)
+		status = "clicked"
+	}
+	if err := database.AdvanceColdEmailStatus(db, job.ApplicationID, status); err != nil {
+		log.Printf("Failed to update cold email for application %d: %v", job.ApplicationID, err)
+	}
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+
+	"aiapply/emailer"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetVerification verifies an address, answering from the cache when possible
+func GetVerification(cache *emailer.VerificationCache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, cache.Verify(c.Param("address")))
+	}
+}
+
+// InvalidateVerification drops the cached result of an address
+func InvalidateVerification(cache *emailer.VerificationCache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := cache.Invalidate(c.Param("address")); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error invalidating verification"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Verification invalidated"})
+	}
+}
+
+// InvalidateDomainVerification drops the cached entries of a domain and its addresses
+func InvalidateDomainVerification(cache *emailer.VerificationCache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := cache.InvalidateDomain(c.Param("domain")); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error invalidating domain"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Domain invalidated"})
+	}
+}
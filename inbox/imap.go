@@ -0,0 +1,265 @@
+package inbox
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"aiapply/models"
+	"aiapply/utils"
+
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+)
+
+// firstPollWindow bounds how far back the first poll of an inbox looks.
+const firstPollWindow = 30 * 24 * time.Hour
+
+var messageIDPattern = regexp.MustCompile(`<[^<>\s]+>`)
+
+// Message holds the headers of an incoming email needed to match it to a cold email.
+type Message struct {
+	UID        uint32
+	MessageID  string
+	InReplyTo  []string
+	References []string
+	From       string
+	Subject    string
+	Date       time.Time
+}
+
+// Mailbox is a read-only view of an inbox.
+type Mailbox interface {
+	// UIDValidity identifies the UID numbering of the mailbox. UIDs from an
+	// earlier validity must not be reused.
+	UIDValidity() uint32
+	// Messages returns the headers of messages with a UID above afterUID.
+	Messages(afterUID uint32) ([]Message, error)
+	// Body returns the raw RFC 5322 message.
+	Body(uid uint32) ([]byte, error)
+	Close() error
+}
+
+// DialFunc opens the inbox described by a user's mail settings.
+type DialFunc func(settings models.MailSettings) (Mailbox, error)
+
+// IMAPConfig describes how to reach an IMAP inbox.
+type IMAPConfig struct {
+	Addr     string
+	Username string
+	Password string
+	// TLS dials with implicit TLS. Otherwise STARTTLS is required, unless
+	// AllowPlaintext lets the password go over a plain connection, as to a
+	// local server standing in during tests.
+	TLS            bool
+	AllowPlaintext bool
+	Mailbox        string
+}
+
+// DialSettings opens the IMAP inbox configured in settings.
+func DialSettings(settings models.MailSettings) (Mailbox, error) {
+	password := ""
+	if settings.Password != "" {
+		var err error
+		password, err = utils.DecryptSecret(settings.Password)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt mail password: %w", err)
+		}
+	}
+	port := settings.IMAPPort
+	if port == 0 {
+		port = 993
+	}
+	return DialIMAP(IMAPConfig{
+		Addr:           net.JoinHostPort(settings.IMAPHost, strconv.Itoa(port)),
+		Username:       settings.Username,
+		Password:       password,
+		TLS:            port == 993,
+		AllowPlaintext: settings.IMAPAllowPlaintext,
+	})
+}
+
+// imapMailbox is a Mailbox backed by an IMAP connection.
+type imapMailbox struct {
+	c        *client.Client
+	validity uint32
+}
+
+// DialIMAP logs in and selects the mailbox read-only.
+func DialIMAP(cfg IMAPConfig) (Mailbox, error) {
+	host, _, _ := net.SplitHostPort(cfg.Addr)
+	tlsConfig := &tls.Config{ServerName: host}
+
+	var c *client.Client
+	var err error
+	if cfg.TLS {
+		c, err = client.DialWithDialerTLS(&net.Dialer{Timeout: 10 * time.Second}, cfg.Addr, tlsConfig)
+	} else {
+		c, err = client.DialWithDialer(&net.Dialer{Timeout: 10 * time.Second}, cfg.Addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to IMAP server: %w", err)
+	}
+	c.Timeout = time.Minute
+
+	if !cfg.TLS {
+		ok, _ := c.SupportStartTLS()
+		switch {
+		case ok:
+			if err := c.StartTLS(tlsConfig); err != nil {
+				c.Logout()
+				return nil, fmt.Errorf("STARTTLS failed: %w", err)
+			}
+		case !cfg.AllowPlaintext:
+			c.Logout()
+			return nil, fmt.Errorf("IMAP server %s offers no TLS; refusing to send the password in plaintext", cfg.Addr)
+		}
+	}
+
+	if err := c.Login(cfg.Username, cfg.Password); err != nil {
+		c.Logout()
+		return nil, fmt.Errorf("IMAP login failed: %w", err)
+	}
+
+	name := cfg.Mailbox
+	if name == "" {
+		name = "INBOX"
+	}
+	status, err := c.Select(name, true)
+	if err != nil {
+		c.Logout()
+		return nil, fmt.Errorf("select %s: %w", name, err)
+	}
+
+	return &imapMailbox{c: c, validity: status.UidValidity}, nil
+}
+
+func (m *imapMailbox) UIDValidity() uint32 { return m.validity }
+
+func (m *imapMailbox) Messages(afterUID uint32) ([]Message, error) {
+	criteria := imap.NewSearchCriteria()
+	if afterUID == 0 {
+		criteria.Since = time.Now().Add(-firstPollWindow)
+	} else {
+		criteria.Uid = new(imap.SeqSet)
+		criteria.Uid.AddRange(afterUID+1, 0)
+	}
+	uids, err := m.c.UidSearch(criteria)
+	if err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+
+	seqset := new(imap.SeqSet)
+	for _, uid := range uids {
+		// "n:*" always matches the newest message, even below n
+		if uid > afterUID {
+			seqset.AddNum(uid)
+		}
+	}
+	if seqset.Empty() {
+		return nil, nil
+	}
+
+	section := &imap.BodySectionName{
+		BodyPartName: imap.BodyPartName{
+			Specifier: imap.HeaderSpecifier,
+			Fields:    []string{"Message-ID", "In-Reply-To", "References", "From", "Subject", "Date"},
+		},
+		Peek: true,
+	}
+	ch := make(chan *imap.Message, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- m.c.UidFetch(seqset, []imap.FetchItem{imap.FetchUid, section.FetchItem()}, ch)
+	}()
+
+	var messages []Message
+	for msg := range ch {
+		body := msg.GetBody(section)
+		if body == nil {
+			continue
+		}
+		parsed, err := parseHeaders(body)
+		if err != nil {
+			continue
+		}
+		parsed.UID = msg.Uid
+		messages = append(messages, parsed)
+	}
+	if err := <-done; err != nil {
+		return nil, fmt.Errorf("fetch: %w", err)
+	}
+	return messages, nil
+}
+
+func (m *imapMailbox) Body(uid uint32) ([]byte, error) {
+	seqset := new(imap.SeqSet)
+	seqset.AddNum(uid)
+	section := &imap.BodySectionName{Peek: true}
+
+	ch := make(chan *imap.Message, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- m.c.UidFetch(seqset, []imap.FetchItem{section.FetchItem()}, ch)
+	}()
+
+	var raw []byte
+	for msg := range ch {
+		if body := msg.GetBody(section); body != nil {
+			raw, _ = io.ReadAll(body)
+		}
+	}
+	if err := <-done; err != nil {
+		return nil, fmt.Errorf("fetch: %w", err)
+	}
+	if raw == nil {
+		return nil, fmt.Errorf("message %d has no body", uid)
+	}
+	return raw, nil
+}
+
+func (m *imapMailbox) Close() error {
+	return m.c.Logout()
+}
+
+// parseHeaders reads the header block returned for a HEADER.FIELDS fetch.
+func parseHeaders(r io.Reader) (Message, error) {
+	msg, err := mail.ReadMessage(io.MultiReader(r, strings.NewReader("\r\n")))
+	if err != nil {
+		return Message{}, err
+	}
+	h := msg.Header
+
+	from := h.Get("From")
+	if addr, err := mail.ParseAddress(from); err == nil {
+		from = addr.Address
+	}
+	date, _ := h.Date()
+
+	dec := new(mime.WordDecoder)
+	subject, err := dec.DecodeHeader(h.Get("Subject"))
+	if err != nil {
+		subject = h.Get("Subject")
+	}
+
+	return Message{
+		MessageID:  strings.TrimSpace(h.Get("Message-ID")),
+		InReplyTo:  messageIDs(h.Get("In-Reply-To")),
+		References: messageIDs(h.Get("References")),
+		From:       strings.ToLower(from),
+		Subject:    subject,
+		Date:       date,
+	}, nil
+}
+
+// messageIDs splits a header holding a list of <message-id> values.
+func messageIDs(value string) []string {
+	return messageIDPattern.FindAllString(value, -1)
+}
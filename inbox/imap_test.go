@@ -0,0 +1,63 @@
+package inbox
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/emersion/go-imap/backend/memory"
+	"github.com/emersion/go-imap/server"
+)
+
+// plainServer runs go-imap's in-memory server without TLS. Its one user is
+// "username" with password "password" and one message in INBOX.
+func plainServer(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := server.New(memory.New())
+	s.AllowInsecureAuth = true
+	go s.Serve(l)
+	t.Cleanup(func() { s.Close() })
+	return l.Addr().String()
+}
+
+func TestDialIMAPRefusesPlaintext(t *testing.T) {
+	addr := plainServer(t)
+	_, err := DialIMAP(IMAPConfig{Addr: addr, Username: "username", Password: "password"})
+	if err == nil || !strings.Contains(err.Error(), "plaintext") {
+		t.Fatalf("DialIMAP without TLS = %v, want a plaintext refusal", err)
+	}
+}
+
+func TestDialIMAPAllowPlaintext(t *testing.T) {
+	addr := plainServer(t)
+	mbox, err := DialIMAP(IMAPConfig{Addr: addr, Username: "username", Password: "password", AllowPlaintext: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mbox.Close()
+
+	if mbox.UIDValidity() == 0 {
+		t.Error("no UIDVALIDITY")
+	}
+	messages, err := mbox.Messages(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("got %d messages, want 1", len(messages))
+	}
+	if messages[0].From != "contact@example.org" || messages[0].Subject != "A little message, just for you" {
+		t.Errorf("message = %+v", messages[0])
+	}
+	body, err := mbox.Body(messages[0].UID)
+	if err != nil || !strings.Contains(string(body), "Hi there :)") {
+		t.Errorf("Body = %q, %v", body, err)
+	}
+
+	if more, err := mbox.Messages(messages[0].UID); err != nil || len(more) != 0 {
+		t.Errorf("Messages after the last UID = %v, %v; want none", more, err)
+	}
+}
@@ -0,0 +1,219 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"aiapply/database"
+	"aiapply/mailqueue"
+	"aiapply/models"
+
+	"gorm.io/gorm"
+)
+
+const pollInterval = 5 * time.Minute
+
+// Poller checks every configured inbox for replies to sent cold emails.
+type Poller struct {
+	db   *gorm.DB
+	dial DialFunc
+}
+
+// NewPoller returns a poller that opens inboxes with dial, normally DialSettings.
+func NewPoller(db *gorm.DB, dial DialFunc) *Poller {
+	return &Poller{db: db, dial: dial}
+}
+
+// Start polls all inboxes until ctx is cancelled.
+func (p *Poller) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			p.PollAll()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// PollAll polls the inbox of every user with IMAP configured.
+func (p *Poller) PollAll() {
+	var all []models.MailSettings
+	if err := p.db.Where("imap_host <> ''").Find(&all).Error; err != nil {
+		log.Printf("Failed to load mail settings for reply polling: %v", err)
+		return
+	}
+	for _, settings := range all {
+		if err := p.Poll(settings); err != nil {
+			log.Printf("Reply polling failed for user %d: %v", settings.UserID, err)
+		}
+	}
+}
+
+// Poll reads the messages that arrived since the last poll of one inbox and
+// records those that answer a sent cold email.
+func (p *Poller) Poll(settings models.MailSettings) error {
+	mbox, err := p.dial(settings)
+	if err != nil {
+		return err
+	}
+	defer mbox.Close()
+
+	lastUID := settings.IMAPLastUID
+	if mbox.UIDValidity() != settings.IMAPUIDValidity {
+		lastUID = 0
+	}
+
+	messages, err := mbox.Messages(lastUID)
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range messages {
+		if msg.UID > lastUID {
+			lastUID = msg.UID
+		}
+		// Our own copies and bounces are not replies
+		if msg.From == "" || strings.EqualFold(msg.From, settings.FromAddress) {
+			continue
+		}
+
+		job, err := p.match(settings.UserID, msg)
+		if err != nil {
+			return err
+		}
+		if job == nil {
+			continue
+		}
+
+		snippet := ""
+		if raw, err := mbox.Body(msg.UID); err == nil {
+			snippet = Snippet(raw)
+		} else {
+			log.Printf("Failed to fetch reply body %d for user %d: %v", msg.UID, settings.UserID, err)
+		}
+		if err := p.record(job, msg, snippet); err != nil {
+			return err
+		}
+	}
+
+	return p.db.Model(&models.MailSettings{}).Where("id = ?", settings.ID).
+		Updates(map[string]interface{}{"imap_uid_validity": mbox.UIDValidity(), "imap_last_uid": lastUID}).Error
+}
+
+// match finds the cold email a message answers. Thread headers pointing at the
+// original or a follow-up win; otherwise the latest email sent to the
+// message's sender is used.
+func (p *Poller) match(userID uint, msg Message) (*models.EmailJob, error) {
+	ids := append(append([]string{}, msg.InReplyTo...), msg.References...)
+
+	var job models.EmailJob
+	if len(ids) > 0 {
+		err := p.db.Where("user_id = ? AND message_id IN ?", userID, ids).First(&job).Error
+		if err == nil {
+			return &job, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		var fu models.FollowUp
+		err = p.db.Where("user_id = ? AND message_id IN ?", userID, ids).First(&fu).Error
+		if err == nil {
+			if err := p.db.First(&job, fu.EmailJobID).Error; err != nil {
+				return nil, err
+			}
+			return &job, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	err := p.db.Where("user_id = ? AND status = ? AND LOWER(sent_to) = ?", userID, models.EmailJobSent, msg.From).
+		Order("sent_at DESC").First(&job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// record stores the reply once, marks the email and application as replied
+// and stops the remaining follow-ups.
+func (p *Poller) record(job *models.EmailJob, msg Message, snippet string) error {
+	if msg.MessageID != "" {
+		var seen int64
+		if err := p.db.Model(&models.EmailReply{}).
+			Where("user_id = ? AND message_id = ?", job.UserID, msg.MessageID).
+			Count(&seen).Error; err != nil {
+			return err
+		}
+		if seen > 0 {
+			return nil
+		}
+	}
+
+	receivedAt := msg.Date
+	if receivedAt.IsZero() {
+		receivedAt = time.Now()
+	}
+
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		reply := models.EmailReply{
+			UserID:        job.UserID,
+			ApplicationID: job.ApplicationID,
+			EmailJobID:    job.ID,
+			MessageID:     msg.MessageID,
+			From:          msg.From,
+			Subject:       msg.Subject,
+			Snippet:       snippet,
+			ReceivedAt:    receivedAt,
+		}
+		if err := tx.Create(&reply).Error; err != nil {
+			return err
+		}
+		res := tx.Model(&models.EmailJob{}).Where("id = ? AND replied_at IS NULL", job.ID).Update("replied_at", receivedAt)
+		if res.Error != nil {
+			return res.Error
+		}
+		// The first reply confirms the pattern of the address it was sent to
+		if res.RowsAffected > 0 {
+			if err := p.learnPattern(tx, job); err != nil {
+				return err
+			}
+		}
+		if err := database.AdvanceColdEmailStatus(tx, job.ApplicationID, "replied"); err != nil {
+			return err
+		}
+		return mailqueue.CancelFollowUps(tx, job.ApplicationID, "reply received")
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Recorded reply from %s to cold email %d", msg.From, job.ID)
+	return nil
+}
+
+// learnPattern records the pattern of the address a replied email went to.
+func (p *Poller) learnPattern(tx *gorm.DB, job *models.EmailJob) error {
+	var candidate models.EmailCandidate
+	err := tx.Where("email_job_id = ? AND address = ?", job.ID, job.SentTo).First(&candidate).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return database.RecordDomainPattern(tx, job.Domain, candidate.Pattern, true)
+}
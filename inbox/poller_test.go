@@ -0,0 +1,212 @@
+package inbox
+
+import (
+	"testing"
+	"time"
+
+	"aiapply/database/dbtest"
+	"aiapply/models"
+
+	"gorm.io/gorm"
+)
+
+// fakeMailbox is an in-memory Mailbox.
+type fakeMailbox struct {
+	validity uint32
+	messages []Message
+	bodies   map[uint32][]byte
+}
+
+func (m *fakeMailbox) UIDValidity() uint32 { return m.validity }
+
+func (m *fakeMailbox) Messages(afterUID uint32) ([]Message, error) {
+	var found []Message
+	for _, msg := range m.messages {
+		if msg.UID > afterUID {
+			found = append(found, msg)
+		}
+	}
+	return found, nil
+}
+
+func (m *fakeMailbox) Body(uid uint32) ([]byte, error) { return m.bodies[uid], nil }
+
+func (m *fakeMailbox) Close() error { return nil }
+
+func (m *fakeMailbox) dial(models.MailSettings) (Mailbox, error) { return m, nil }
+
+const (
+	userID        = 1
+	applicationID = 7
+)
+
+// setup stores an inbox and a cold email sent to jane@acme.com, with one
+// follow-up still to go.
+func setup(t *testing.T) (*gorm.DB, models.MailSettings, models.EmailJob) {
+	db := dbtest.Open(t, &models.MailSettings{}, &models.EmailJob{}, &models.FollowUp{}, &models.EmailReply{},
+		&models.ColdEmail{}, &models.EmailCandidate{}, &models.DomainPattern{})
+
+	settings := models.MailSettings{UserID: userID, FromAddress: "me@example.com", IMAPHost: "imap.example.com", IMAPUIDValidity: 1, IMAPLastUID: 10}
+	sentAt := time.Now().Add(-48 * time.Hour)
+	job := models.EmailJob{
+		UserID: userID, ApplicationID: applicationID, Domain: "acme.com", Status: models.EmailJobSent,
+		SentTo: "jane@acme.com", MessageID: "<original@example.com>", SentAt: &sentAt,
+	}
+	if err := db.Create(&settings).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&job).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range []any{
+		&models.ColdEmail{UserID: userID, ApplicationID: applicationID, Status: "sent"},
+		&models.EmailCandidate{EmailJobID: job.ID, UserID: userID, ApplicationID: applicationID, Address: job.SentTo, Pattern: "first"},
+		&models.FollowUp{UserID: userID, ApplicationID: applicationID, EmailJobID: job.ID, Step: 1,
+			DueAt: time.Now().Add(24 * time.Hour), Status: models.FollowUpPending, MessageID: "<followup@example.com>"},
+	} {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db, settings, job
+}
+
+// replies returns the replies recorded for the cold email.
+func replies(t *testing.T, db *gorm.DB) []models.EmailReply {
+	var found []models.EmailReply
+	if err := db.Order("id").Find(&found).Error; err != nil {
+		t.Fatal(err)
+	}
+	return found
+}
+
+func TestPollMatchesInReplyTo(t *testing.T) {
+	db, settings, job := setup(t)
+	mbox := &fakeMailbox{validity: 1, messages: []Message{
+		// already seen in an earlier poll
+		{UID: 9, MessageID: "<old@acme.com>", InReplyTo: []string{"<original@example.com>"}, From: "jane@acme.com"},
+		// a colleague answering on the thread
+		{UID: 11, MessageID: "<reply@acme.com>", InReplyTo: []string{"<original@example.com>"}, From: "hr@acme.com", Subject: "Re: Hello"},
+		// not a reply to anything we sent
+		{UID: 12, MessageID: "<news@shop.com>", From: "news@shop.com"},
+	}, bodies: map[uint32][]byte{11: []byte("Subject: Re: Hello\r\n\r\nThanks, let's talk.\r\n")}}
+
+	if err := NewPoller(db, mbox.dial).Poll(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	got := replies(t, db)
+	if len(got) != 1 {
+		t.Fatalf("recorded %d replies, want 1", len(got))
+	}
+	if got[0].EmailJobID != job.ID || got[0].MessageID != "<reply@acme.com>" || got[0].From != "hr@acme.com" {
+		t.Errorf("reply = %+v, want <reply@acme.com> from hr@acme.com to job %d", got[0], job.ID)
+	}
+	if got[0].Snippet == "" {
+		t.Error("reply has no snippet")
+	}
+
+	var updated models.EmailJob
+	db.First(&updated, job.ID)
+	if updated.RepliedAt == nil {
+		t.Error("email job not marked replied")
+	}
+	var coldEmail models.ColdEmail
+	db.Where("application_id = ?", applicationID).First(&coldEmail)
+	if coldEmail.Status != "replied" {
+		t.Errorf("cold email status = %q, want replied", coldEmail.Status)
+	}
+	var pattern models.DomainPattern
+	if err := db.Where("domain = ? AND pattern = ?", "acme.com", "first").First(&pattern).Error; err != nil || pattern.Replied != 1 {
+		t.Errorf("pattern of the replied address not learned: %+v, %v", pattern, err)
+	}
+
+	var stored models.MailSettings
+	db.First(&stored, settings.ID)
+	if stored.IMAPLastUID != 12 || stored.IMAPUIDValidity != 1 {
+		t.Errorf("poll position = %d/%d, want 1/12", stored.IMAPUIDValidity, stored.IMAPLastUID)
+	}
+
+	// a second poll of the same messages records nothing new
+	if err := NewPoller(db, mbox.dial).Poll(stored); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(replies(t, db)); n != 1 {
+		t.Errorf("second poll left %d replies, want 1", n)
+	}
+}
+
+func TestPollMatchesFollowUpReferences(t *testing.T) {
+	db, settings, job := setup(t)
+	mbox := &fakeMailbox{validity: 1, messages: []Message{
+		{UID: 11, MessageID: "<reply@acme.com>", References: []string{"<followup@example.com>"}, From: "hr@acme.com"},
+	}}
+
+	if err := NewPoller(db, mbox.dial).Poll(settings); err != nil {
+		t.Fatal(err)
+	}
+	got := replies(t, db)
+	if len(got) != 1 || got[0].EmailJobID != job.ID {
+		t.Fatalf("replies = %+v, want one to job %d", got, job.ID)
+	}
+}
+
+func TestPollFallsBackToSender(t *testing.T) {
+	db, settings, job := setup(t)
+	mbox := &fakeMailbox{validity: 1, messages: []Message{
+		// a new message with no thread headers from the person we wrote to
+		{UID: 11, MessageID: "<fresh@acme.com>", From: "jane@acme.com"},
+		// our own copy is not a reply
+		{UID: 12, MessageID: "<copy@example.com>", From: "me@example.com"},
+	}}
+
+	if err := NewPoller(db, mbox.dial).Poll(settings); err != nil {
+		t.Fatal(err)
+	}
+	got := replies(t, db)
+	if len(got) != 1 || got[0].EmailJobID != job.ID || got[0].From != "jane@acme.com" {
+		t.Fatalf("replies = %+v, want one from jane@acme.com to job %d", got, job.ID)
+	}
+}
+
+func TestPollRestartsAfterUIDValidityChange(t *testing.T) {
+	db, settings, job := setup(t)
+	// the mailbox was rebuilt: UIDs start over below the stored position
+	mbox := &fakeMailbox{validity: 2, messages: []Message{
+		{UID: 3, MessageID: "<reply@acme.com>", InReplyTo: []string{"<original@example.com>"}, From: "jane@acme.com"},
+	}}
+
+	if err := NewPoller(db, mbox.dial).Poll(settings); err != nil {
+		t.Fatal(err)
+	}
+	got := replies(t, db)
+	if len(got) != 1 || got[0].EmailJobID != job.ID {
+		t.Fatalf("replies = %+v, want one to job %d", got, job.ID)
+	}
+	var stored models.MailSettings
+	db.First(&stored, settings.ID)
+	if stored.IMAPUIDValidity != 2 || stored.IMAPLastUID != 3 {
+		t.Errorf("poll position = %d/%d, want 2/3", stored.IMAPUIDValidity, stored.IMAPLastUID)
+	}
+}
+
+func TestPollCancelsFollowUps(t *testing.T) {
+	db, settings, _ := setup(t)
+	mbox := &fakeMailbox{validity: 1, messages: []Message{
+		{UID: 11, MessageID: "<reply@acme.com>", InReplyTo: []string{"<original@example.com>"}, From: "jane@acme.com"},
+	}}
+
+	var followUp models.FollowUp
+	db.Where("application_id = ?", applicationID).First(&followUp)
+	if followUp.Status != models.FollowUpPending {
+		t.Fatalf("follow-up status before poll = %q", followUp.Status)
+	}
+
+	if err := NewPoller(db, mbox.dial).Poll(settings); err != nil {
+		t.Fatal(err)
+	}
+	db.First(&followUp, followUp.ID)
+	if followUp.Status != models.FollowUpCancelled {
+		t.Errorf("follow-up status = %q, want %q", followUp.Status, models.FollowUpCancelled)
+	}
+}
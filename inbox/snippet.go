@@ -0,0 +1,106 @@
+package inbox
+
+import (
+	"bytes"
+	"encoding/base64"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+const snippetLength = 500
+
+var (
+	tagPattern   = regexp.MustCompile(`(?s)<[^>]*>`)
+	wrotePattern = regexp.MustCompile(`(?m)^On .+wrote:\s*$`)
+)
+
+// Snippet returns the start of the new text of a reply, leaving out the
+// quoted original below it.
+func Snippet(raw []byte) string {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return ""
+	}
+	text := textPart(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+
+	if loc := wrotePattern.FindStringIndex(text); loc != nil {
+		text = text[:loc[0]]
+	}
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), ">") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	text = strings.Join(strings.Fields(strings.Join(lines, " ")), " ")
+
+	if r := []rune(text); len(r) > snippetLength {
+		text = string(r[:snippetLength]) + "…"
+	}
+	return text
+}
+
+// textPart walks a MIME body and returns its text, preferring text/plain over
+// text/html.
+func textPart(contentType, encoding string, body io.Reader) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		var htmlText string
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err != nil {
+				break
+			}
+			text := textPart(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if text == "" {
+				continue
+			}
+			ct := strings.ToLower(part.Header.Get("Content-Type"))
+			if strings.HasPrefix(ct, "text/html") {
+				if htmlText == "" {
+					htmlText = text
+				}
+				continue
+			}
+			return text
+		}
+		return htmlText
+	}
+
+	if !strings.HasPrefix(mediaType, "text/") {
+		return ""
+	}
+
+	switch strings.ToLower(encoding) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+	data, err := io.ReadAll(io.LimitReader(body, 1<<20))
+	if err != nil && len(data) == 0 {
+		return ""
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	if mediaType == "text/html" {
+		// Gmail and Outlook put the quoted original in a blockquote
+		if i := strings.Index(strings.ToLower(text), "<blockquote"); i >= 0 {
+			text = text[:i]
+		}
+		text = html.UnescapeString(tagPattern.ReplaceAllString(text, " "))
+	}
+	return text
+}
@@ -0,0 +1,143 @@
+package indeed
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"aiapply/scraper"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// JobDetail holds the fields of one Indeed search result.
+type JobDetail struct {
+	ID             string // Indeed's job key
+	Role           string
+	Company        string
+	Location       string
+	MoreLocations  string // e.g. "+2 locations"
+	Salary         string
+	EmploymentType string // e.g. "Full-time", "Internship"
+	Attributes     []string
+	Snippet        string
+	PostedAgo      string // e.g. "3 days ago", "Today"
+	EmployerActive string // e.g. "2 days ago", shown instead of the posted date
+	URL            string
+	Sponsored      bool
+}
+
+var (
+	employmentTypes = []string{"Full-time", "Part-time", "Internship", "Contract", "Temporary", "Permanent", "Fresher", "Freelance", "Volunteer"}
+	payPattern      = regexp.MustCompile(`(?i)[₹$€£]|\b(a|an|per) (year|month|week|day|hour)\b`)
+)
+
+// ScrapeJobs reads a saved Indeed search results page. Job links point at
+// the Indeed site the page was saved from, e.g. in.indeed.com.
+func ScrapeJobs(reader io.Reader) ([]JobDetail, error) {
+	doc, err := goquery.NewDocumentFromReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse HTML: %w", err)
+	}
+	sel := scraper.SelectorsFor("indeed")
+	host := siteHost(doc)
+
+	var jobs []JobDetail
+	sel.Find(doc.Selection, "card").Each(func(_ int, s *goquery.Selection) {
+		link := sel.Find(s, "job_link").First()
+		id, _ := link.Attr("data-jk")
+		href, _ := link.Attr("href")
+
+		job := JobDetail{
+			ID:        id,
+			Role:      sel.Text(s, "role"),
+			Company:   sel.Text(s, "company"),
+			Sponsored: strings.HasPrefix(href, "/pagead/"),
+		}
+		if job.ID != "" {
+			job.URL = "https://" + host + "/viewjob?jk=" + job.ID
+		}
+
+		// the snippet is a short list of bullet points
+		snippet := sel.Find(s, "snippet").First()
+		if items := snippet.Find("li"); items.Length() > 0 {
+			var lines []string
+			items.Each(func(_ int, li *goquery.Selection) {
+				lines = append(lines, cleanText(li.Text()))
+			})
+			job.Snippet = strings.Join(lines, "\n")
+		} else {
+			job.Snippet = cleanText(snippet.Text())
+		}
+
+		// "Pune, Maharashtra" with "+2 locations" nested inside
+		location := sel.Find(s, "location").First()
+		job.Location = ownText(location)
+		job.MoreLocations = cleanText(location.Children().Text())
+
+		sel.Find(s, "attribute").Each(func(_ int, a *goquery.Selection) {
+			text := cleanText(a.Text())
+			switch {
+			case text == "":
+			case job.Salary == "" && payPattern.MatchString(text):
+				job.Salary = text
+			case job.EmploymentType == "" && isEmploymentType(text):
+				job.EmploymentType = text
+			default:
+				job.Attributes = append(job.Attributes, text)
+			}
+		})
+
+		// the date reads "Posted 3 days ago" or "Active 2 days ago" after
+		// a visually hidden label
+		date := sel.Find(s, "date").First().Clone()
+		date.Find(".visually-hidden").Remove()
+		switch text := cleanText(date.Text()); {
+		case strings.HasPrefix(text, "Posted "):
+			job.PostedAgo = strings.TrimPrefix(text, "Posted ")
+		case strings.HasPrefix(text, "Active "):
+			job.EmployerActive = strings.TrimPrefix(text, "Active ")
+		}
+
+		if job.ID != "" || job.Role != "" {
+			jobs = append(jobs, job)
+		}
+	})
+
+	return jobs, nil
+}
+
+// siteHost returns the Indeed host the page was saved from.
+func siteHost(doc *goquery.Document) string {
+	for _, sel := range []string{"link[rel='canonical']", "meta[property='og:url']"} {
+		s := doc.Find(sel).First()
+		href, ok := s.Attr("href")
+		if !ok {
+			href, _ = s.Attr("content")
+		}
+		if u, err := url.Parse(href); err == nil && strings.HasSuffix(u.Host, "indeed.com") {
+			return u.Host
+		}
+	}
+	return "www.indeed.com"
+}
+
+func isEmploymentType(text string) bool {
+	for _, t := range employmentTypes {
+		if strings.EqualFold(text, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// ownText is the text of s without that of its child elements.
+func ownText(s *goquery.Selection) string {
+	return cleanText(s.Clone().Children().Remove().End().Text())
+}
+
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,73 @@
+package indeed
+
+import (
+	"io"
+	"strings"
+
+	"aiapply/scraper"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func init() {
+	scraper.Register(Scraper{})
+}
+
+// Scraper maps Indeed search results into scraper jobs.
+type Scraper struct{}
+
+func (Scraper) Platform() string { return "indeed" }
+
+func (Scraper) Fingerprint() scraper.Fingerprint {
+	return scraper.Fingerprint{
+		Hosts:     []string{"indeed.com"},
+		SiteNames: []string{"Indeed"},
+		Markers:   []string{"card"},
+		Strings:   []string{"mosaic-provider-jobcards", "indeed.com/"},
+	}
+}
+
+// FetchHints follows the next-page arrow below the results.
+func (Scraper) FetchHints() scraper.FetchHints {
+	sel := scraper.SelectorsFor("indeed")
+	return scraper.FetchHints{Card: sel.Group("card"), Next: sel.Group("next_page")}
+}
+
+func (Scraper) CountCards(r io.Reader) (int, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return 0, err
+	}
+	return scraper.SelectorsFor("indeed").Find(doc.Selection, "card").Length(), nil
+}
+
+func (Scraper) Scrape(r io.Reader) ([]scraper.Job, error) {
+	details, err := ScrapeJobs(r)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]scraper.Job, 0, len(details))
+	for _, d := range details {
+		job := scraper.Job{
+			ID:       d.ID,
+			Platform: "indeed",
+			Role:     d.Role,
+			Company:  d.Company,
+			Location: d.Location,
+			Salary:   d.Salary,
+			URL:      d.URL,
+		}
+		job.SetExtra("employment_type", d.EmploymentType)
+		job.SetExtra("more_locations", d.MoreLocations)
+		job.SetExtra("attributes", strings.Join(d.Attributes, ", "))
+		job.SetExtra("description", d.Snippet)
+		job.SetExtra("posted_ago", d.PostedAgo)
+		job.SetExtra("employer_active", d.EmployerActive)
+		if d.Sponsored {
+			job.SetExtra("sponsored", "true")
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,44 @@
+package indeed
+
+import (
+	"testing"
+	"time"
+
+	"aiapply/scraper"
+	"aiapply/scraper/scrapertest"
+)
+
+func TestScrapeFixture(t *testing.T) {
+	scrapertest.CheckFixture(t, Scraper{}, "indeed.html", []scrapertest.Want{
+		{
+			ID: "6a1f0c2e9b7d4a31", Role: "Golang Developer", Company: "Persistent Systems", Location: "Pune, Maharashtra",
+			Salary: "₹12,00,000 - ₹18,00,000 a year", URL: "https://in.indeed.com/viewjob?jk=6a1f0c2e9b7d4a31",
+			Extras:       map[string]string{"employment_type": "Full-time", "posted_ago": "3 days ago"},
+			Age:          72 * time.Hour,
+			Compensation: &scraper.Compensation{Min: 1200000, Max: 1800000, Currency: "INR", Period: "year"}, City: "Pune",
+		},
+		{
+			// sponsored cards show when the employer was active, not when the job was posted
+			ID: "0c9e77d1a2f54b88", Role: "Backend Engineer (Go/Python)", Company: "Tekion", Location: "Hybrid work in Pune, Maharashtra",
+			URL:     "https://in.indeed.com/viewjob?jk=0c9e77d1a2f54b88",
+			Extras:  map[string]string{"employment_type": "Full-time", "sponsored": "true", "employer_active": "2 days ago"},
+			Undated: true, Policy: "hybrid", City: "Pune",
+		},
+		{
+			ID: "91b3f0e4d6c27a15", Role: "Software Developer Intern - Go", Company: "Fynd", Location: "Remote",
+			Salary: "₹25,000 - ₹35,000 a month", URL: "https://in.indeed.com/viewjob?jk=91b3f0e4d6c27a15",
+			Extras:       map[string]string{"employment_type": "Internship", "attributes": "6 months", "posted_ago": "Today"},
+			Compensation: &scraper.Compensation{Min: 25000, Max: 35000, Currency: "INR", Period: "month", Stipend: true}, Policy: "remote",
+		},
+		{
+			ID: "3e5d8a0b7f1c9e26", Role: "Senior Site Reliability Engineer", Company: "Mastercard", Location: "Pune, Maharashtra",
+			URL:    "https://in.indeed.com/viewjob?jk=3e5d8a0b7f1c9e26",
+			Extras: map[string]string{"employment_type": "Full-time", "more_locations": "+2 locations", "attributes": "Day shift", "posted_ago": "30+ days ago"},
+			Age:    30 * 24 * time.Hour, City: "Pune",
+		},
+	})
+}
+
+func TestCountCards(t *testing.T) {
+	scrapertest.CheckCardCount(t, Scraper{}, "indeed.html", 4)
+}
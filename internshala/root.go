@@ -0,0 +1,83 @@
+package internshala
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"aiapply/scraper"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// JobDetail holds the fields of one Internshala internship or job card.
+type JobDetail struct {
+	ID         string
+	Type       string // "internship" or "job"
+	Role       string
+	Company    string
+	LogoURL    string
+	URL        string
+	Location   string // "Work from home" for remote internships
+	Duration   string // internships only, e.g. "3 Months"
+	Experience string // jobs only, e.g. "0-2 years"
+	Salary     string // stipend for internships, CTC for jobs
+	PostedAgo  string // e.g. "2 days ago", "Few hours ago"
+	Labels     []string
+}
+
+// ScrapeListings reads a saved Internshala internship or job search page.
+func ScrapeListings(reader io.Reader) ([]JobDetail, error) {
+	doc, err := goquery.NewDocumentFromReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse HTML: %w", err)
+	}
+	sel := scraper.SelectorsFor("internshala")
+
+	var listings []JobDetail
+	sel.Find(doc.Selection, "card").Each(func(_ int, s *goquery.Selection) {
+		id, _ := s.Attr("internshipid")
+		typ, _ := s.Attr("employment_type")
+		href, ok := s.Attr("data-href")
+		if !ok {
+			href = sel.Attr(s, "job_link", "href")
+		}
+
+		listing := JobDetail{
+			ID:         id,
+			Type:       typ,
+			Role:       sel.Text(s, "role"),
+			Company:    sel.Text(s, "company"),
+			LogoURL:    sel.Attr(s, "logo", "src"),
+			URL:        absoluteURL(href),
+			Location:   sel.Text(s, "location"),
+			Duration:   sel.Text(s, "duration"),
+			Experience: sel.Text(s, "experience"),
+			Salary:     sel.Text(s, "salary"),
+			PostedAgo:  sel.Text(s, "posted_ago"),
+		}
+		if listing.Type == "" {
+			listing.Type = "internship"
+			if strings.HasPrefix(href, "/job/") {
+				listing.Type = "job"
+			}
+		}
+		sel.Find(s, "label").Each(func(_ int, label *goquery.Selection) {
+			if text := strings.TrimSpace(label.Text()); text != "" {
+				listing.Labels = append(listing.Labels, text)
+			}
+		})
+		if listing.ID != "" || listing.Role != "" {
+			listings = append(listings, listing)
+		}
+	})
+
+	return listings, nil
+}
+
+func absoluteURL(href string) string {
+	if strings.HasPrefix(href, "/") {
+		return "https://internshala.com" + href
+	}
+	return href
+}
@@ -0,0 +1,73 @@
+package internshala
+
+import (
+	"io"
+	"strings"
+
+	"aiapply/scraper"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func init() {
+	scraper.Register(Scraper{})
+}
+
+// Scraper maps Internshala internships and jobs into scraper jobs.
+type Scraper struct{}
+
+func (Scraper) Platform() string { return "internshala" }
+
+func (Scraper) Fingerprint() scraper.Fingerprint {
+	return scraper.Fingerprint{
+		Hosts:     []string{"internshala.com"},
+		SiteNames: []string{"Internshala"},
+		Markers:   []string{"card"},
+		Strings:   []string{"internshala.com"},
+	}
+}
+
+// FetchHints follows the arrow below the results.
+func (Scraper) FetchHints() scraper.FetchHints {
+	sel := scraper.SelectorsFor("internshala")
+	return scraper.FetchHints{Card: sel.Group("card"), Next: sel.Group("next_page")}
+}
+
+func (Scraper) CountCards(r io.Reader) (int, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return 0, err
+	}
+	return scraper.SelectorsFor("internshala").Find(doc.Selection, "card").Length(), nil
+}
+
+func (Scraper) Scrape(r io.Reader) ([]scraper.Job, error) {
+	listings, err := ScrapeListings(r)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]scraper.Job, 0, len(listings))
+	for _, l := range listings {
+		job := scraper.Job{
+			ID:       l.ID,
+			Platform: "internshala",
+			Role:     l.Role,
+			Company:  l.Company,
+			Location: l.Location,
+			Salary:   l.Salary,
+			URL:      l.URL,
+		}
+		job.SetExtra("type", l.Type)
+		job.SetExtra("company_photo_url", l.LogoURL)
+		job.SetExtra("duration", l.Duration)
+		job.SetExtra("experience", l.Experience)
+		job.SetExtra("posted_ago", l.PostedAgo)
+		job.SetExtra("labels", strings.Join(l.Labels, ", "))
+		if strings.EqualFold(l.Location, "Work from home") {
+			job.SetExtra("mode", l.Location)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
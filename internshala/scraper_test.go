@@ -0,0 +1,47 @@
+package internshala
+
+import (
+	"testing"
+	"time"
+
+	"aiapply/scraper"
+	"aiapply/scraper/scrapertest"
+)
+
+func TestScrapeFixture(t *testing.T) {
+	stipend := func(min, max float64) *scraper.Compensation {
+		return &scraper.Compensation{Min: min, Max: max, Currency: "INR", Period: "month", Stipend: true}
+	}
+	scrapertest.CheckFixture(t, Scraper{}, "internshala.html", []scrapertest.Want{
+		{
+			ID: "2816734", Role: "Web Development", Company: "Krishivaas Technologies", Location: "Bangalore", Salary: "₹ 10,000 - 15,000 /month",
+			URL:          "https://internshala.com/internship/detail/web-development-internship-in-bangalore-at-krishivaas-technologies1720510012",
+			Extras:       map[string]string{"type": "internship", "duration": "3 Months", "labels": "Internship, Part time allowed", "posted_ago": "Just now"},
+			Compensation: stipend(10000, 15000), City: "Bengaluru",
+		},
+		{
+			ID: "2812009", Role: "Full Stack Development", Company: "CodeClouds", Location: "Work from home", Salary: "₹ 8,000 /month",
+			URL:    "https://internshala.com/internship/detail/work-from-home-full-stack-development-internship-at-codeclouds1720112233",
+			Extras: map[string]string{"type": "internship", "duration": "6 Months", "mode": "Work from home", "posted_ago": "2 days ago"},
+			Age:    48 * time.Hour, Compensation: stipend(8000, 8000), Policy: "remote",
+		},
+		{
+			// unpaid, so no compensation
+			ID: "2809981", Role: "React.js Development", Company: "FinStreet", Location: "Bangalore, Mumbai", Salary: "Unpaid",
+			URL:    "https://internshala.com/internship/detail/react-js-development-internship-in-bangalore-mumbai-at-finstreet1719876655",
+			Extras: map[string]string{"type": "internship", "duration": "2 Months", "posted_ago": "1 week ago"},
+			Age:    7 * 24 * time.Hour, City: "Bengaluru",
+		},
+		{
+			// a fresher job rather than an internship: a yearly salary, not a stipend
+			ID: "2815502", Role: "Junior Web Developer", Company: "Zoplar", Location: "Bangalore", Salary: "₹ 3,00,000 - 4,50,000",
+			URL:    "https://internshala.com/job/detail/fresher-junior-web-developer-job-in-bangalore-at-zoplar1720400001",
+			Extras: map[string]string{"type": "job", "experience": "0-2 years", "posted_ago": "Few hours ago"},
+			Age:    3 * time.Hour, Compensation: &scraper.Compensation{Min: 300000, Max: 450000, Currency: "INR", Period: "year"}, City: "Bengaluru",
+		},
+	})
+}
+
+func TestCountCards(t *testing.T) {
+	scrapertest.CheckCardCount(t, Scraper{}, "internshala.html", 4)
+}
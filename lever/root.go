@@ -0,0 +1,93 @@
+package lever
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Posting is one listing of the Lever postings API,
+// api.lever.co/v0/postings/{site}?mode=json.
+type Posting struct {
+	ID               string       `json:"id"`
+	Text             string       `json:"text"`
+	HostedURL        string       `json:"hostedUrl"`
+	ApplyURL         string       `json:"applyUrl"`
+	CreatedAt        int64        `json:"createdAt"` // Unix milliseconds
+	Categories       Categories   `json:"categories"`
+	WorkplaceType    string       `json:"workplaceType"` // remote, hybrid, onsite or unspecified
+	Country          string       `json:"country"`
+	DescriptionPlain string       `json:"descriptionPlain"`
+	Description      string       `json:"description"`
+	SalaryRange      *SalaryRange `json:"salaryRange"`
+}
+
+type Categories struct {
+	Commitment   string   `json:"commitment"` // e.g. "Full-time", "Intern"
+	Department   string   `json:"department"`
+	Team         string   `json:"team"`
+	Location     string   `json:"location"`
+	AllLocations []string `json:"allLocations"`
+}
+
+type SalaryRange struct {
+	Currency string  `json:"currency"`
+	Interval string  `json:"interval"` // e.g. "per-year-salary", "per-hour-wage"
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+}
+
+// ScrapePostings reads a Lever postings listing: the API's array or a
+// single posting.
+func ScrapePostings(reader io.Reader) ([]Posting, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+
+	var postings []Posting
+	if len(data) > 0 && data[0] == '{' {
+		var p Posting
+		err = json.Unmarshal(data, &p)
+		postings = []Posting{p}
+	} else {
+		err = json.Unmarshal(data, &postings)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not parse Lever JSON: %w", err)
+	}
+	return postings, nil
+}
+
+// Site is the company's Lever site name, e.g. "acme" in
+// jobs.lever.co/acme/{id}.
+func (p Posting) Site() string {
+	u, err := url.Parse(p.HostedURL)
+	if err != nil || !strings.HasSuffix(u.Host, "lever.co") {
+		return ""
+	}
+	site, _, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	return site
+}
+
+// Locations lists every location of the posting, its main one first.
+func (p Posting) Locations() []string {
+	locations := p.Categories.AllLocations
+	if len(locations) == 0 && p.Categories.Location != "" {
+		locations = []string{p.Categories.Location}
+	}
+	return locations
+}
+
+func (p Posting) PostedAt() *time.Time {
+	if p.CreatedAt == 0 {
+		return nil
+	}
+	t := time.UnixMilli(p.CreatedAt).UTC()
+	return &t
+}
@@ -0,0 +1,146 @@
+package lever
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"aiapply/scraper"
+)
+
+func init() {
+	scraper.Register(Scraper{})
+}
+
+// Scraper maps Lever postings into scraper jobs.
+type Scraper struct{}
+
+func (Scraper) Platform() string { return "lever" }
+
+func (Scraper) Fingerprint() scraper.Fingerprint {
+	return scraper.Fingerprint{
+		Strings: []string{`"hostedUrl"`, "lever.co/"},
+	}
+}
+
+var siteName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// BoardURL accepts a site name, a jobs.lever.co page or an API link. Sites
+// hosted in the EU keep their own API host.
+func (Scraper) BoardURL(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	site, api := ref, "api.lever.co"
+	if !siteName.MatchString(ref) {
+		if !strings.Contains(ref, "://") {
+			ref = "https://" + ref
+		}
+		u, err := url.Parse(ref)
+		if err != nil {
+			return "", fmt.Errorf("not a Lever board: %s", ref)
+		}
+		host := strings.ToLower(u.Hostname())
+		if host != "lever.co" && !strings.HasSuffix(host, ".lever.co") {
+			return "", fmt.Errorf("not a Lever board: %s", ref)
+		}
+		parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if strings.HasPrefix(host, "api.") {
+			api = host
+			if len(parts) < 3 || parts[1] != "postings" {
+				return "", fmt.Errorf("not a Lever board: %s", ref)
+			}
+			site = parts[2]
+		} else {
+			api = "api." + strings.TrimPrefix(host, "jobs.")
+			site = parts[0]
+		}
+	}
+	if !siteName.MatchString(site) {
+		return "", fmt.Errorf("not a Lever board: %s", ref)
+	}
+	return "https://" + api + "/v0/postings/" + site + "?mode=json", nil
+}
+
+func (Scraper) CountCards(r io.Reader) (int, error) {
+	postings, err := ScrapePostings(r)
+	return len(postings), err
+}
+
+// Lever's salary intervals as Compensation periods
+var intervals = map[string]string{
+	"per-hour-wage":    scraper.PeriodHour,
+	"per-week-salary":  scraper.PeriodWeek,
+	"per-month-salary": scraper.PeriodMonth,
+	"per-year-salary":  scraper.PeriodYear,
+}
+
+func (Scraper) Scrape(r io.Reader) ([]scraper.Job, error) {
+	postings, err := ScrapePostings(r)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]scraper.Job, 0, len(postings))
+	for _, p := range postings {
+		if p.Text == "" {
+			continue
+		}
+		job := scraper.Job{
+			ID:       p.ID,
+			Platform: "lever",
+			Role:     strings.TrimSpace(p.Text),
+			// postings do not name the company; its site name does
+			Company:  scraper.BoardCompany(p.Site()),
+			Location: strings.Join(p.Locations(), " • "),
+			URL:      p.HostedURL,
+			PostedAt: p.PostedAt(),
+		}
+		job.Salary, job.Compensation = salary(p.SalaryRange)
+
+		description := p.DescriptionPlain
+		if description == "" {
+			description = scraper.PlainText(p.Description)
+		}
+		job.SetExtra("board", p.Site())
+		job.SetExtra("department", p.Categories.Department)
+		job.SetExtra("team", p.Categories.Team)
+		job.SetExtra("employment_type", p.Categories.Commitment)
+		if p.WorkplaceType != "unspecified" {
+			job.SetExtra("mode", p.WorkplaceType)
+		}
+		job.SetExtra("apply_url", p.ApplyURL)
+		job.SetExtra("description", strings.TrimSpace(description))
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// salary prints a salary range as "USD 120,000 - 150,000 per year".
+func salary(r *SalaryRange) (string, *scraper.Compensation) {
+	if r == nil || (r.Min == 0 && r.Max == 0) {
+		return "", nil
+	}
+	min, max := r.Min, r.Max
+	if max == 0 {
+		max = min
+	}
+	if min == 0 {
+		min = max
+	}
+
+	text := scraper.FormatAmount(min)
+	if max != min {
+		text += " - " + scraper.FormatAmount(max)
+	}
+	if r.Currency != "" {
+		text = r.Currency + " " + text
+	}
+	period, ok := intervals[r.Interval]
+	if !ok {
+		// one-time payments and the like are left to ParseCompensation
+		return text, nil
+	}
+	text += " per " + period
+	return text, &scraper.Compensation{Min: min, Max: max, Currency: r.Currency, Period: period}
+}
@@ -0,0 +1,115 @@
+package lever
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"aiapply/scraper"
+)
+
+// stubClient sends every request to a local server, keeping the path and
+// query, and records the URLs it was asked for.
+type stubClient struct {
+	server    *httptest.Server
+	requested []string
+}
+
+func (c *stubClient) Do(req *http.Request) (*http.Response, error) {
+	c.requested = append(c.requested, req.URL.String())
+	local := req.Clone(req.Context())
+	local.URL.Scheme = "http"
+	local.URL.Host = strings.TrimPrefix(c.server.URL, "http://")
+	return c.server.Client().Do(local)
+}
+
+// boardServer serves the saved Tracxn postings and 404 for any other site.
+func boardServer(t *testing.T) *stubClient {
+	postings, err := os.ReadFile("lever.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v0/postings/tracxn" || r.URL.Query().Get("mode") != "json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(postings)
+	}))
+	t.Cleanup(server.Close)
+	return &stubClient{server: server}
+}
+
+func TestBoardURL(t *testing.T) {
+	const api = "https://api.lever.co/v0/postings/tracxn?mode=json"
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"tracxn", api},
+		{"https://jobs.lever.co/tracxn", api},
+		{"jobs.lever.co/tracxn/3f6b1a52-8d0e-4c1b-9b7a-0e5d2f4c8a11", api},
+		{"https://api.lever.co/v0/postings/tracxn?mode=json", api},
+		{"https://JOBS.LEVER.CO:443/tracxn", api},
+		{"https://jobs.eu.lever.co/tracxn", "https://api.eu.lever.co/v0/postings/tracxn?mode=json"},
+		{"https://api.evillever.co/v0/postings/acme", ""},
+		{"https://jobs.notlever.co/acme", ""},
+		{"https://lever.co.evil.test/acme", ""},
+		{"https://api.lever.co/v1/acme", ""},
+		{"https://jobs.lever.co/tr acxn", ""},
+	}
+	for _, tt := range tests {
+		got, err := Scraper{}.BoardURL(tt.ref)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("BoardURL(%q) = %q, want an error", tt.ref, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("BoardURL(%q) = %q, %v; want %q", tt.ref, got, err, tt.want)
+		}
+	}
+}
+
+func TestImportBoard(t *testing.T) {
+	client := boardServer(t)
+	jobs, err := scraper.ImportBoard(context.Background(), client, "lever", "https://jobs.lever.co/tracxn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(client.requested) != 1 || client.requested[0] != "https://api.lever.co/v0/postings/tracxn?mode=json" {
+		t.Errorf("requested %v", client.requested)
+	}
+
+	want := []scraper.Job{
+		{ID: "3f6b1a52-8d0e-4c1b-9b7a-0e5d2f4c8a11", Role: "Software Engineer, Data Platform", Company: "Tracxn", Salary: "INR 1,800,000 - 2,600,000 per year", URL: "https://jobs.lever.co/tracxn/3f6b1a52-8d0e-4c1b-9b7a-0e5d2f4c8a11"},
+		{ID: "a91c0d77-2f43-4e68-8b1f-5c3e9d7a6b02", Role: "Research Analyst Intern", Company: "Tracxn", Salary: "INR 25,000 per month", URL: "https://jobs.lever.co/tracxn/a91c0d77-2f43-4e68-8b1f-5c3e9d7a6b02"},
+		{ID: "c27e5f10-6a9d-4b3c-a1e8-7d4f0b2c9e33", Role: "Enterprise Account Executive", Company: "Tracxn", URL: "https://jobs.lever.co/tracxn/c27e5f10-6a9d-4b3c-a1e8-7d4f0b2c9e33"},
+	}
+	if len(jobs) != len(want) {
+		t.Fatalf("got %d jobs, want %d", len(jobs), len(want))
+	}
+	for i, w := range want {
+		got := jobs[i]
+		if got.Platform != "lever" || got.ID != w.ID || got.Role != w.Role || got.Company != w.Company || got.Salary != w.Salary || got.URL != w.URL {
+			t.Errorf("job %d = %q %q %q %q %q, want %q %q %q %q %q", i,
+				got.ID, got.Role, got.Company, got.Salary, got.URL, w.ID, w.Role, w.Company, w.Salary, w.URL)
+		}
+	}
+	if c := jobs[1].Compensation; c == nil || c.Min != 25000 || c.Period != scraper.PeriodMonth {
+		t.Errorf("intern compensation = %+v, want 25000 per month", c)
+	}
+}
+
+func TestImportBoardNotFound(t *testing.T) {
+	client := boardServer(t)
+	_, err := scraper.ImportBoard(context.Background(), client, "lever", "nosuchsite")
+	if err == nil || !strings.Contains(err.Error(), "no lever board") {
+		t.Errorf("ImportBoard of a missing board = %v, want a not found error", err)
+	}
+}
@@ -0,0 +1,194 @@
+package linkedin
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"aiapply/scraper"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+var (
+	jobViewPattern   = regexp.MustCompile(`/jobs/view/(?:[^/?]*-)?(\d+)`)
+	applicantPattern = regexp.MustCompile(`(?i)([\d,]+)\s+(?:people clicked apply|applicants?)`)
+	postedPattern    = regexp.MustCompile(`(?i)^(?:reposted|posted)?\s*(\d+\s+\w+\s+ago|just now)$`)
+)
+
+// Values LinkedIn shows in the job insight pills and criteria list
+var (
+	workplaceTypes  = []string{"On-site", "Remote", "Hybrid"}
+	employmentTypes = []string{"Full-time", "Part-time", "Contract", "Temporary", "Internship", "Volunteer", "Other"}
+	seniorityLevels = []string{"Internship", "Entry level", "Associate", "Mid-Senior level", "Director", "Executive", "Not Applicable"}
+)
+
+// ParseJobDetail reads a saved LinkedIn job page, either the job view itself
+// or a search page with a job open in the detail pane. Both the signed-in
+// layout and the public guest page are understood.
+func ParseJobDetail(r io.Reader) (*JobDetail, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse HTML: %w", err)
+	}
+
+	sel := scraper.SelectorsFor("linkedin-job")
+	page := doc.Selection
+
+	detail := &JobDetail{
+		Role:    sel.Text(page, "role"),
+		Company: sel.Text(page, "company"),
+	}
+
+	detail.ID = sel.Attr(page, "apply_button", "data-job-id")
+	if detail.ID == "" {
+		for _, selector := range sel["job_link"] {
+			href, _ := doc.Find(selector).First().Attr("href")
+			if m := jobViewPattern.FindStringSubmatch(href); m != nil {
+				detail.ID = m[1]
+				break
+			}
+		}
+	}
+
+	// Signed-in layout: "Bengaluru, Karnataka, India · 9 hours ago · 53 applicants"
+	sel.Find(page, "top_card_details").Each(func(i int, s *goquery.Selection) {
+		text := cleanText(s.Text())
+		switch {
+		case text == "" || text == "·":
+		case i == 0:
+			detail.Location = text
+		case postedPattern.MatchString(text):
+			detail.PostedAgo = postedPattern.FindStringSubmatch(text)[1]
+		case applicantPattern.MatchString(text):
+			detail.Applicants = parseCount(applicantPattern.FindStringSubmatch(text)[1])
+		}
+	})
+
+	// Guest layout
+	if detail.Location == "" {
+		detail.Location = sel.Text(page, "location")
+	}
+	if detail.PostedAgo == "" {
+		detail.PostedAgo = sel.Text(page, "posted_ago")
+	}
+	if detail.Applicants == 0 {
+		if m := applicantPattern.FindStringSubmatch(sel.Text(page, "applicants")); m != nil {
+			detail.Applicants = parseCount(m[1])
+		}
+	}
+	sel.Find(page, "criteria").Each(func(_ int, s *goquery.Selection) {
+		value := cleanText(sel.Find(s, "criteria_value").Text())
+		switch sel.Text(s, "criteria_label") {
+		case "Seniority level":
+			detail.Seniority = value
+		case "Employment type":
+			detail.EmploymentType = value
+		}
+	})
+
+	// Insight pills such as "On-site", "Full-time", "Mid-Senior level"
+	sel.Find(page, "insights").Each(func(_ int, s *goquery.Selection) {
+		text := cleanText(s.Find("[aria-hidden='true']").Text())
+		if text == "" {
+			text = cleanText(s.Text())
+		}
+		for _, part := range strings.Split(text, "·") {
+			part = strings.TrimSpace(part)
+			if v := match(part, workplaceTypes); v != "" && detail.WorkplaceType == "" {
+				detail.WorkplaceType = v
+			} else if v := match(part, employmentTypes); v != "" && detail.EmploymentType == "" {
+				detail.EmploymentType = v
+			} else if v := match(part, seniorityLevels); v != "" && detail.Seniority == "" {
+				detail.Seniority = v
+			}
+		}
+	})
+	if detail.WorkplaceType == "" {
+		// "Recro · Bengaluru, Karnataka, India (On-site)"
+		sub := sel.Text(page, "sticky_header")
+		for _, v := range workplaceTypes {
+			if strings.Contains(sub, "("+v+")") {
+				detail.WorkplaceType = v
+			}
+		}
+	}
+
+	detail.Description = blockText(sel.Find(page, "description").First())
+	detail.Description = strings.TrimSpace(strings.TrimPrefix(detail.Description, "About the job"))
+
+	sel.Find(page, "hiring_team").Each(func(_ int, s *goquery.Selection) {
+		name := cleanText(s.Text())
+		for _, seen := range detail.HiringTeam {
+			if seen == name {
+				return
+			}
+		}
+		if name != "" {
+			detail.HiringTeam = append(detail.HiringTeam, name)
+		}
+	})
+
+	if detail.Role == "" && detail.Description == "" {
+		return nil, fmt.Errorf("no job details found in page")
+	}
+	return detail, nil
+}
+
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func match(text string, values []string) string {
+	for _, v := range values {
+		if strings.EqualFold(text, v) {
+			return v
+		}
+	}
+	return ""
+}
+
+func parseCount(s string) int {
+	n, _ := strconv.Atoi(strings.ReplaceAll(s, ",", ""))
+	return n
+}
+
+// blockText returns the text of a description, one line per paragraph,
+// list item or line break.
+func blockText(sel *goquery.Selection) string {
+	var b strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+			return
+		case html.ElementNode:
+			switch n.Data {
+			case "br":
+				b.WriteString("\n")
+				return
+			case "p", "div", "li", "ul", "ol", "h1", "h2", "h3", "h4":
+				b.WriteString("\n")
+				defer b.WriteString("\n")
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	for _, n := range sel.Nodes {
+		walk(n)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		if line = cleanText(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
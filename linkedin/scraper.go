@@ -0,0 +1,117 @@
+package linkedin
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"aiapply/scraper"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func init() {
+	scraper.Register(Scraper{})
+	scraper.Register(DetailScraper{})
+}
+
+// Scraper maps LinkedIn job search results into scraper jobs.
+type Scraper struct{}
+
+func (Scraper) Platform() string { return "linkedin" }
+
+func (Scraper) Fingerprint() scraper.Fingerprint {
+	return scraper.Fingerprint{
+		Hosts:     []string{"linkedin.com"},
+		SiteNames: []string{"LinkedIn"},
+		Markers:   []string{"card"},
+		Strings:   []string{"com.linkedin.voyager"},
+	}
+}
+
+// FetchHints follows the numbered pages under the results list.
+func (Scraper) FetchHints() scraper.FetchHints {
+	sel := scraper.SelectorsFor("linkedin")
+	return scraper.FetchHints{Card: sel.Group("card"), Next: sel.Group("next_page")}
+}
+
+// CountCards counts result cards, including those without a job ID.
+func (Scraper) CountCards(r io.Reader) (int, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return 0, err
+	}
+	return scraper.SelectorsFor("linkedin").Find(doc.Selection, "card").Length(), nil
+}
+
+func (Scraper) Scrape(r io.Reader) ([]scraper.Job, error) {
+	listings, err := Jobscrapper(r)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]scraper.Job, 0, len(listings))
+	for _, l := range listings {
+		jobs = append(jobs, scraper.Job{
+			ID:       l.ID,
+			Platform: "linkedin",
+			Role:     l.Role,
+			Company:  l.Company,
+			Location: l.Location,
+			Salary:   l.Salary,
+			URL:      "https://www.linkedin.com/jobs/view/" + l.ID + "/",
+		})
+	}
+	return jobs, nil
+}
+
+// DetailScraper maps a saved LinkedIn job page into a scraper job. It shares
+// the platform and ID of the search results, so the job stored from a search
+// page gains the details. The hiring team's names are kept in the
+// "hiring_team" extra, comma separated, for cold emailing them.
+type DetailScraper struct{}
+
+func (DetailScraper) Platform() string { return "linkedin-job" }
+
+// Fingerprint tells a job page from a search page by its single title;
+// search pages with a job open also list cards and go to "linkedin".
+func (DetailScraper) Fingerprint() scraper.Fingerprint {
+	return scraper.Fingerprint{
+		Hosts:     []string{"linkedin.com"},
+		SiteNames: []string{"LinkedIn"},
+		Markers:   []string{"role"},
+		Strings:   []string{"com.linkedin.voyager"},
+	}
+}
+
+func (DetailScraper) FetchHints() scraper.FetchHints {
+	return scraper.FetchHints{Card: scraper.SelectorsFor("linkedin-job").Group("role")}
+}
+
+func (DetailScraper) Scrape(r io.Reader) ([]scraper.Job, error) {
+	d, err := ParseJobDetail(r)
+	if err != nil {
+		return nil, err
+	}
+
+	job := scraper.Job{
+		ID:       d.ID,
+		Platform: "linkedin",
+		Role:     d.Role,
+		Company:  d.Company,
+		Location: d.Location,
+	}
+	if d.ID != "" {
+		job.URL = "https://www.linkedin.com/jobs/view/" + d.ID + "/"
+	}
+	job.SetExtra("description", d.Description)
+	job.SetExtra("seniority", d.Seniority)
+	job.SetExtra("employment_type", d.EmploymentType)
+	job.SetExtra("workplace_type", d.WorkplaceType)
+	job.SetExtra("posted_ago", d.PostedAgo)
+	job.SetExtra("hiring_team", strings.Join(d.HiringTeam, ", "))
+	if d.Applicants > 0 {
+		job.SetExtra("applicants", strconv.Itoa(d.Applicants))
+	}
+	return []scraper.Job{job}, nil
+}
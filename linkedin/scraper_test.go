@@ -0,0 +1,102 @@
+package linkedin
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"aiapply/scraper"
+)
+
+func scrapeFile(t *testing.T, s scraper.Scraper, name string) []scraper.Job {
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	jobs, err := s.Scrape(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jobs
+}
+
+func TestParseJobDetail(t *testing.T) {
+	tests := []struct {
+		file string
+		want JobDetail
+	}{
+		// public job view of a signed-out visitor
+		{"job_view.html", JobDetail{
+			ID: "4281730551", Role: "Senior Software Engineer - Backend", Company: "Flipkart",
+			Location: "Bengaluru, Karnataka, India", Seniority: "Mid-Senior level", EmploymentType: "Full-time",
+			Applicants: 200, PostedAgo: "2 days ago", HiringTeam: []string{"Ananya Rao", "Karthik Menon"},
+		}},
+		// search page with a job open in the detail pane
+		{"network.html", JobDetail{
+			ID: "4278494082", Role: "Principal Engineer", Company: "Recro",
+			Location: "Bengaluru, Karnataka, India", EmploymentType: "Full-time", WorkplaceType: "On-site",
+			Applicants: 53, PostedAgo: "9 hours ago", HiringTeam: []string{"Vaishali S."},
+		}},
+	}
+	for _, tt := range tests {
+		f, err := os.Open(tt.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ParseJobDetail(f)
+		f.Close()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.file, err)
+		}
+
+		if got.Description == "" {
+			t.Errorf("%s: no description", tt.file)
+		}
+		got.Description = ""
+		if !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("%s: detail = %+v, want %+v", tt.file, *got, tt.want)
+		}
+	}
+}
+
+func TestParseJobDetailDescription(t *testing.T) {
+	f, err := os.Open("job_view.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	detail, err := ParseJobDetail(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(detail.Description, "\n")
+	if len(lines) != 8 || lines[0] != "About the team" || lines[3] != "Design and run high-throughput services in Java and Go" {
+		t.Errorf("description lines = %q", lines)
+	}
+}
+
+// The detail page must land on the row of the search result it opens.
+func TestDetailScraperMatchesSearch(t *testing.T) {
+	details := scrapeFile(t, DetailScraper{}, "network.html")
+	if len(details) != 1 {
+		t.Fatalf("got %d detail jobs, want 1", len(details))
+	}
+	detail := details[0]
+	if detail.Extras["hiring_team"] != "Vaishali S." || detail.Extras["posted_ago"] != "9 hours ago" || detail.Extras["applicants"] != "53" {
+		t.Errorf("extras = %v", detail.Extras)
+	}
+
+	for _, job := range scrapeFile(t, Scraper{}, "network.html") {
+		if job.ID == detail.ID {
+			if job.Platform != detail.Platform || job.URL != detail.URL {
+				t.Errorf("search job %s/%s, detail job %s/%s", job.Platform, job.URL, detail.Platform, detail.URL)
+			}
+			return
+		}
+	}
+	t.Errorf("no search result with ID %s", detail.ID)
+}
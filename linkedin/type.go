@@ -8,3 +8,18 @@ type Job struct {
 	Location string
 	Salary   string
 }
+
+// JobDetail holds the fields of a single job page.
+type JobDetail struct {
+	ID             string
+	Role           string
+	Company        string
+	Location       string
+	Description    string
+	Seniority      string // e.g. "Mid-Senior level"
+	EmploymentType string // e.g. "Full-time"
+	WorkplaceType  string // "On-site", "Remote" or "Hybrid"
+	Applicants     int
+	PostedAgo      string // e.g. "9 hours ago"
+	HiringTeam     []string
+}
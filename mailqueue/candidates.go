@@ -0,0 +1,77 @@
+package mailqueue
+
+import (
+	"log"
+	"time"
+
+	"aiapply/emailer"
+	"aiapply/models"
+
+	"gorm.io/gorm/clause"
+)
+
+// candidates stores the generated addresses of a job, keeping the rows of an
+// earlier attempt, and returns them in the order they will be tried.
+func (q *Queue) candidates(job *models.EmailJob, perms []emailer.Permutation) ([]models.EmailCandidate, error) {
+	rows := make([]models.EmailCandidate, 0, len(perms))
+	for _, p := range perms {
+		rows = append(rows, models.EmailCandidate{
+			EmailJobID:    job.ID,
+			UserID:        job.UserID,
+			ApplicationID: job.ApplicationID,
+			EmployeeName:  job.EmployeeName,
+			Address:       p.Address,
+			Pattern:       p.Pattern,
+			Verification:  models.CandidateNotChecked,
+		})
+	}
+	if err := q.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var stored []models.EmailCandidate
+	if err := q.db.Where("email_job_id = ?", job.ID).Find(&stored).Error; err != nil {
+		return nil, err
+	}
+	byAddress := make(map[string]models.EmailCandidate, len(stored))
+	for _, c := range stored {
+		byAddress[c.Address] = c
+	}
+
+	ordered := make([]models.EmailCandidate, 0, len(perms))
+	for _, p := range perms {
+		if c, ok := byAddress[p.Address]; ok {
+			ordered = append(ordered, c)
+		}
+	}
+	return ordered, nil
+}
+
+// recordVerification saves the outcome of verifying a candidate.
+func (q *Queue) recordVerification(c *models.EmailCandidate, res emailer.VerificationResult) {
+	now := time.Now()
+	c.Verification = res.Result
+	c.VerificationError = res.Reason
+	c.SMTPCode = res.SMTPCode
+	c.CheckedAt = &now
+	if err := q.db.Save(c).Error; err != nil {
+		log.Printf("Failed to save verification of %s: %v", c.Address, err)
+	}
+}
+
+// recordSend saves the outcome of sending to the chosen candidate.
+func (q *Queue) recordSend(c *models.EmailCandidate, sent *emailer.SentMessage, sendErr error) {
+	if sendErr != nil {
+		c.SendResult = models.CandidateSendFailed
+		c.SendError = sendErr.Error()
+	} else {
+		now := time.Now()
+		c.SendResult = models.CandidateSent
+		c.SendError = ""
+		c.MessageID = sent.MessageID
+		c.SentAt = &now
+	}
+	if err := q.db.Save(c).Error; err != nil {
+		log.Printf("Failed to save send result of %s: %v", c.Address, err)
+	}
+}
@@ -0,0 +1,274 @@
+package mailqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"aiapply/database"
+	"aiapply/emailer"
+	"aiapply/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const followUpInterval = time.Minute
+
+// errReplied stops a follow-up because the recipient already answered.
+var errReplied = errors.New("reply received")
+
+// errCancelled stops a follow-up cancelled, or left without an application,
+// while it was being sent.
+var errCancelled = errors.New("follow-up cancelled")
+
+// ScheduleFollowUps creates the pending follow-ups of the job's sequence,
+// counting each step's delay from the moment the original email went out.
+func ScheduleFollowUps(db *gorm.DB, job *models.EmailJob) error {
+	if job.SequenceID == nil || job.SentAt == nil {
+		return nil
+	}
+
+	var seq models.Sequence
+	err := db.Preload("Steps").Where("id = ? AND user_id = ?", *job.SequenceID, job.UserID).First(&seq).Error
+	if err != nil {
+		return err
+	}
+	if len(seq.Steps) == 0 {
+		return nil
+	}
+
+	steps := seq.Steps
+	sort.Slice(steps, func(i, j int) bool { return steps[i].DelayDays < steps[j].DelayDays })
+
+	followUps := make([]models.FollowUp, 0, len(steps))
+	for i, step := range steps {
+		followUps = append(followUps, models.FollowUp{
+			UserID:        job.UserID,
+			ApplicationID: job.ApplicationID,
+			EmailJobID:    job.ID,
+			Step:          i + 1,
+			TemplateID:    step.TemplateID,
+			DueAt:         job.SentAt.Add(time.Duration(step.DelayDays) * 24 * time.Hour),
+			Status:        models.FollowUpPending,
+		})
+	}
+	return db.Create(&followUps).Error
+}
+
+// CancelFollowUps stops every follow-up of an application that has not gone
+// out yet, including one being sent right now.
+func CancelFollowUps(db *gorm.DB, applicationID uint, reason string) error {
+	return db.Model(&models.FollowUp{}).
+		Where("application_id = ? AND status IN ?", applicationID, []string{models.FollowUpPending, models.FollowUpSending}).
+		Updates(map[string]interface{}{"status": models.FollowUpCancelled, "last_error": reason}).Error
+}
+
+// Scheduler sends follow-ups once they are due and the thread is still unanswered.
+type Scheduler struct {
+	db *gorm.DB
+}
+
+// NewScheduler returns a follow-up scheduler backed by db.
+func NewScheduler(db *gorm.DB) *Scheduler {
+	return &Scheduler{db: db}
+}
+
+// Start runs the scheduler until ctx is cancelled. Follow-ups interrupted
+// by a previous shutdown are failed first.
+func (s *Scheduler) Start(ctx context.Context) {
+	if err := s.resume(); err != nil {
+		log.Printf("Failed to resume follow-ups: %v", err)
+	}
+	go func() {
+		ticker := time.NewTicker(followUpInterval)
+		defer ticker.Stop()
+		for {
+			for s.sendNext() {
+				if ctx.Err() != nil {
+					return
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// sendNext handles the oldest due follow-up. It reports whether a follow-up
+// was found.
+func (s *Scheduler) sendNext() bool {
+	fu, err := s.claim()
+	if err != nil {
+		log.Printf("Failed to claim follow-up: %v", err)
+		return false
+	}
+	if fu == nil {
+		return false
+	}
+	s.finish(fu, s.send(s.db, fu))
+	return true
+}
+
+// claim moves the next due follow-up to sending in a short transaction, so
+// that two servers never send the same step and no row stays locked while
+// the mail server is talked to. It returns nil when nothing is due.
+func (s *Scheduler) claim() (*models.FollowUp, error) {
+	var fu models.FollowUp
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND due_at <= ?", models.FollowUpPending, time.Now()).
+			Order("due_at").
+			First(&fu).Error
+		if err != nil {
+			return err
+		}
+
+		fu.Status = models.FollowUpSending
+		fu.Attempts++
+		return tx.Save(&fu).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &fu, nil
+}
+
+// finish records the outcome of a send. Failures that may be transient are
+// retried with exponential backoff, like Queue.finish does for email jobs.
+// Only a follow-up still being sent is written, so a cancellation made in
+// the meantime stands.
+func (s *Scheduler) finish(fu *models.FollowUp, err error) {
+	updates := map[string]interface{}{"last_error": ""}
+	var perm permanentError
+	switch {
+	case err == nil:
+		updates["status"] = models.FollowUpSent
+		updates["message_id"] = fu.MessageID
+		updates["sent_at"] = fu.SentAt
+	case errors.Is(err, errReplied), errors.Is(err, errCancelled):
+		updates["status"] = models.FollowUpCancelled
+		updates["last_error"] = err.Error()
+	case errors.As(err, &perm) || fu.Attempts >= DefaultMaxAttempts:
+		updates["status"] = models.FollowUpFailed
+		updates["last_error"] = err.Error()
+		log.Printf("Follow-up %d failed after %d attempts: %v", fu.ID, fu.Attempts, err)
+	default:
+		dueAt := time.Now().Add(time.Duration(1<<fu.Attempts) * time.Minute)
+		updates["status"] = models.FollowUpPending
+		updates["last_error"] = err.Error()
+		updates["due_at"] = dueAt
+		log.Printf("Follow-up %d attempt %d failed, retrying at %s: %v", fu.ID, fu.Attempts, dueAt.Format(time.RFC3339), err)
+	}
+	res := s.db.Model(&models.FollowUp{}).
+		Where("id = ? AND status = ?", fu.ID, models.FollowUpSending).
+		Updates(updates)
+	if res.Error != nil {
+		log.Printf("Failed to save follow-up %d: %v", fu.ID, res.Error)
+	}
+}
+
+// resume fails follow-ups that were being sent when the server went down;
+// like email jobs, they may have gone out already.
+func (s *Scheduler) resume() error {
+	res := s.db.Model(&models.FollowUp{}).
+		Where("status = ?", models.FollowUpSending).
+		Updates(map[string]interface{}{"status": models.FollowUpFailed, "last_error": interruptedSend})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		log.Printf("Failed %d follow-ups interrupted during send", res.RowsAffected)
+	}
+	return nil
+}
+
+func (s *Scheduler) send(db *gorm.DB, fu *models.FollowUp) error {
+	var job models.EmailJob
+	if err := db.First(&job, fu.EmailJobID).Error; err != nil {
+		return fmt.Errorf("fetch email job: %w", err)
+	}
+	if job.RepliedAt != nil {
+		return errReplied
+	}
+	var replied int64
+	if err := db.Model(&models.ColdEmail{}).
+		Where("application_id = ? AND status = ?", fu.ApplicationID, "replied").
+		Count(&replied).Error; err != nil {
+		return err
+	}
+	if replied > 0 {
+		return errReplied
+	}
+
+	// The application may have moved on since the follow-up was claimed
+	var app models.JobApplication
+	if err := db.Select("id").First(&app, fu.ApplicationID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errCancelled
+		}
+		return fmt.Errorf("fetch application: %w", err)
+	}
+	var current models.FollowUp
+	if err := db.Select("status").First(&current, fu.ID).Error; err != nil {
+		return fmt.Errorf("fetch follow-up: %w", err)
+	}
+	if current.Status != models.FollowUpSending {
+		return errCancelled
+	}
+	if job.MessageID == "" {
+		return permanentError{"original email has no Message-ID"}
+	}
+
+	var user models.User
+	if err := db.First(&user, fu.UserID).Error; err != nil {
+		return fmt.Errorf("fetch user: %w", err)
+	}
+
+	tmpl := emailer.DefaultFollowUpTemplate()
+	if fu.TemplateID != nil {
+		custom, err := database.GetTemplate(db, fu.UserID, *fu.TemplateID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return permanentError{fmt.Sprintf("template %d not found", *fu.TemplateID)}
+		}
+		if err != nil {
+			return fmt.Errorf("fetch template %d: %w", *fu.TemplateID, err)
+		}
+		tmpl = *custom
+	}
+
+	// Reply to the latest message of the thread and reference all of them
+	var previous []models.FollowUp
+	if err := db.Where("email_job_id = ? AND status = ? AND step < ?", job.ID, models.FollowUpSent, fu.Step).
+		Order("step").Find(&previous).Error; err != nil {
+		return err
+	}
+	thread := emailer.Thread{Subject: job.Subject, References: []string{job.MessageID}}
+	for _, p := range previous {
+		if p.MessageID != "" {
+			thread.References = append(thread.References, p.MessageID)
+		}
+	}
+	thread.InReplyTo = thread.References[len(thread.References)-1]
+
+	data := emailer.NewTemplateData(user, job.EmployeeName, job.CompanyName, job.JobTitle)
+	sent, err := emailer.SendFollowUpEmail(db, job.SentTo, job.TrackingToken, user, tmpl, data, thread)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	fu.MessageID = sent.MessageID
+	fu.SentAt = &now
+	log.Printf("Sent follow-up %d to %s for job %s", fu.Step, job.SentTo, job.JobTitle)
+	return nil
+}
@@ -0,0 +1,62 @@
+package mailqueue
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"aiapply/database/dbtest"
+	"aiapply/models"
+)
+
+func TestCancelDuringSendStands(t *testing.T) {
+	db := dbtest.Open(t, &models.FollowUp{})
+	due := models.FollowUp{ApplicationID: 7, Step: 1, Status: models.FollowUpPending, DueAt: time.Now().Add(-time.Minute)}
+	if err := db.Create(&due).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewScheduler(db)
+	fu, err := s.claim()
+	if err != nil || fu == nil {
+		t.Fatalf("claim = %v, %v", fu, err)
+	}
+	// the application's status changes while the mail server is slow
+	if err := CancelFollowUps(db, 7, "application status changed to Interviewing"); err != nil {
+		t.Fatal(err)
+	}
+	s.finish(fu, errors.New("421 try again later"))
+
+	var stored models.FollowUp
+	db.First(&stored, fu.ID)
+	if stored.Status != models.FollowUpCancelled {
+		t.Errorf("follow-up is %q after a transient failure, want it to stay cancelled", stored.Status)
+	}
+}
+
+func TestSendStopsCancelledFollowUp(t *testing.T) {
+	db := dbtest.Open(t, &models.FollowUp{}, &models.EmailJob{}, &models.ColdEmail{}, &models.JobApplication{})
+	app := models.JobApplication{UserID: 1, Status: "Applied"}
+	if err := db.Create(&app).Error; err != nil {
+		t.Fatal(err)
+	}
+	job := models.EmailJob{UserID: 1, ApplicationID: app.ID, Status: models.EmailJobSent, MessageID: "<original@example.com>"}
+	if err := db.Create(&job).Error; err != nil {
+		t.Fatal(err)
+	}
+	fu := models.FollowUp{UserID: 1, ApplicationID: app.ID, EmailJobID: job.ID, Step: 1, Status: models.FollowUpCancelled}
+	if err := db.Create(&fu).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewScheduler(db).send(db, &fu); !errors.Is(err, errCancelled) {
+		t.Errorf("send of a cancelled follow-up = %v, want errCancelled", err)
+	}
+
+	db.Delete(&app)
+	fu.Status = models.FollowUpSending
+	db.Save(&fu)
+	if err := NewScheduler(db).send(db, &fu); !errors.Is(err, errCancelled) {
+		t.Errorf("send for a deleted application = %v, want errCancelled", err)
+	}
+}
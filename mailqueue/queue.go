@@ -0,0 +1,309 @@
+package mailqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"aiapply/database"
+	"aiapply/emailer"
+	"aiapply/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	DefaultWorkers     = 4
+	DefaultMaxAttempts = 3
+	pollInterval       = 2 * time.Second
+)
+
+// permanentError marks a job failure that retrying cannot fix.
+type permanentError struct{ msg string }
+
+func (e permanentError) Error() string { return e.msg }
+
+// Queue is a Postgres-backed pool of workers that verifies and sends queued
+// cold emails. Jobs survive restarts because their state lives in email_jobs.
+type Queue struct {
+	db      *gorm.DB
+	workers int
+	cache   *emailer.VerificationCache
+}
+
+// New returns a queue that runs the given number of workers against db,
+// verifying addresses through cache.
+func New(db *gorm.DB, workers int, cache *emailer.VerificationCache) *Queue {
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	return &Queue{db: db, workers: workers, cache: cache}
+}
+
+// Enqueue stores one queued job per employee name of a cold email application.
+// It is meant to run inside the transaction that creates the application.
+func Enqueue(tx *gorm.DB, app models.JobApplication) error {
+	if len(app.EmployeeNames) == 0 {
+		return nil
+	}
+
+	jobs := make([]models.EmailJob, 0, len(app.EmployeeNames))
+	for _, name := range app.EmployeeNames {
+		jobs = append(jobs, models.EmailJob{
+			UserID:        app.UserID,
+			ApplicationID: app.ID,
+			EmployeeName:  strings.TrimSpace(name),
+			Domain:        app.Domain,
+			JobTitle:      app.JobTitle,
+			CompanyName:   app.CompanyName,
+			TemplateID:    app.TemplateID,
+			SequenceID:    app.SequenceID,
+			Platform:      app.Platform,
+			Status:        models.EmailJobQueued,
+			MaxAttempts:   DefaultMaxAttempts,
+			NextAttemptAt: time.Now(),
+			TrackingToken: emailer.NewTrackingToken(),
+		})
+	}
+	return tx.Create(&jobs).Error
+}
+
+// Start settles jobs interrupted by a previous shutdown and launches the
+// workers. Workers stop when ctx is cancelled.
+func (q *Queue) Start(ctx context.Context) error {
+	if err := q.resume(); err != nil {
+		return fmt.Errorf("resume email jobs: %w", err)
+	}
+	for i := 0; i < q.workers; i++ {
+		go q.work(ctx)
+	}
+	log.Printf("Email queue started with %d workers", q.workers)
+	return nil
+}
+
+// interruptedSend is the error of a send cut short by a shutdown. The mail server may
+// have accepted the message already, so it is not sent again on its own.
+const interruptedSend = "interrupted during send, check before retrying"
+
+// resume re-queues jobs that were being verified when the server went down.
+// Jobs that were being sent are failed instead, as retrying them could
+// deliver the same email twice.
+func (q *Queue) resume() error {
+	res := q.db.Model(&models.EmailJob{}).
+		Where("status = ?", models.EmailJobVerifying).
+		Updates(map[string]interface{}{"status": models.EmailJobQueued, "next_attempt_at": time.Now()})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		log.Printf("Resumed %d unfinished email jobs", res.RowsAffected)
+	}
+
+	res = q.db.Model(&models.EmailJob{}).
+		Where("status = ?", models.EmailJobSending).
+		Updates(map[string]interface{}{"status": models.EmailJobFailed, "last_error": interruptedSend})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		log.Printf("Failed %d email jobs interrupted during send", res.RowsAffected)
+	}
+	return nil
+}
+
+func (q *Queue) work(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain everything that is due before going back to sleep.
+		for {
+			job, err := q.claim()
+			if err != nil {
+				log.Printf("Failed to claim email job: %v", err)
+				break
+			}
+			if job == nil {
+				break
+			}
+			q.finish(job, q.process(job))
+			if ctx.Err() != nil {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// claim locks the next due job and moves it to verifying. It returns nil when
+// there is nothing to do.
+func (q *Queue) claim() (*models.EmailJob, error) {
+	var job models.EmailJob
+	err := q.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.EmailJobQueued, time.Now()).
+			Order("next_attempt_at").
+			First(&job).Error
+		if err != nil {
+			return err
+		}
+
+		job.Status = models.EmailJobVerifying
+		job.Attempts++
+		return tx.Save(&job).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// process finds a deliverable address for the job's employee and sends the
+// application email to it.
+func (q *Queue) process(job *models.EmailJob) error {
+	var user models.User
+	if err := q.db.First(&user, job.UserID).Error; err != nil {
+		return fmt.Errorf("fetch user: %w", err)
+	}
+
+	// Fail fast before probing mailboxes if the user cannot send anything
+	if _, err := emailer.SenderForUser(q.db, job.UserID); err != nil {
+		if errors.Is(err, emailer.ErrNoMailSettings) {
+			return permanentError{err.Error()}
+		}
+		return err
+	}
+
+	tmpl := emailer.DefaultTemplate()
+	if job.TemplateID != nil {
+		custom, err := database.GetTemplate(q.db, job.UserID, *job.TemplateID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return permanentError{fmt.Sprintf("template %d not found", *job.TemplateID)}
+			}
+			return err
+		}
+		tmpl = *custom
+	}
+
+	parts := strings.Fields(job.EmployeeName)
+	if len(parts) < 2 {
+		return permanentError{fmt.Sprintf("invalid employee name %q", job.EmployeeName)}
+	}
+	firstName, lastName := parts[0], parts[len(parts)-1]
+	patterns := emailer.GeneratePatterns(firstName, lastName, job.Domain)
+	if len(patterns) == 0 {
+		return permanentError{fmt.Sprintf("no address can be guessed from employee name %q", job.EmployeeName)}
+	}
+
+	learned, err := database.GetDomainPatterns(q.db, job.Domain)
+	if err != nil {
+		return fmt.Errorf("fetch domain patterns: %w", err)
+	}
+	rates, err := database.GetPatternSuccessRates(q.db)
+	if err != nil {
+		return fmt.Errorf("fetch pattern success rates: %w", err)
+	}
+	perms := emailer.RankPatterns(patterns, learned, rates)
+
+	candidates, err := q.candidates(job, perms)
+	if err != nil {
+		return fmt.Errorf("store candidates: %w", err)
+	}
+
+	var chosen *models.EmailCandidate
+	for i := range candidates {
+		res := q.cache.Verify(candidates[i].Address)
+		q.recordVerification(&candidates[i], res)
+		if res.Result == emailer.VerificationValid {
+			chosen = &candidates[i]
+			if err := database.RecordDomainPattern(q.db, job.Domain, chosen.Pattern, false); err != nil {
+				log.Printf("Failed to record pattern %s for %s: %v", chosen.Pattern, job.Domain, err)
+			}
+			break
+		}
+		if res.CatchAll {
+			// Every guess would pass, so none of them is worth sending to
+			return permanentError{fmt.Sprintf("%s accepts mail for any address, so no guessed address can be verified", job.Domain)}
+		}
+	}
+	if chosen == nil {
+		return fmt.Errorf("no deliverable address found at %s", job.Domain)
+	}
+	to := chosen.Address
+
+	if err := q.setStatus(job, models.EmailJobSending); err != nil {
+		return err
+	}
+
+	log.Printf("Sending cold email to %s for job %s", to, job.JobTitle)
+	data := emailer.NewTemplateData(user, job.EmployeeName, job.CompanyName, job.JobTitle)
+	if job.TrackingToken == "" {
+		job.TrackingToken = emailer.NewTrackingToken()
+	}
+	sent, err := emailer.SendApplicationEmail(q.db, to, job.TrackingToken, user, tmpl, data)
+	q.recordSend(chosen, sent, err)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	job.SentTo = to
+	job.MessageID = sent.MessageID
+	job.Subject = sent.Subject
+	job.SentAt = &now
+	return nil
+}
+
+// finish records the outcome of a processed job, scheduling a retry with
+// exponential backoff when the failure may be transient.
+func (q *Queue) finish(job *models.EmailJob, err error) {
+	if err == nil {
+		job.Status = models.EmailJobSent
+		job.LastError = ""
+		if err := q.db.Save(job).Error; err != nil {
+			log.Printf("Failed to mark email job %d as sent: %v", job.ID, err)
+		}
+		if err := database.AdvanceColdEmailStatus(q.db, job.ApplicationID, "sent"); err != nil {
+			log.Printf("Failed to update cold email for application %d: %v", job.ApplicationID, err)
+		}
+		if err := ScheduleFollowUps(q.db, job); err != nil {
+			log.Printf("Failed to schedule follow-ups for email job %d: %v", job.ID, err)
+		}
+		// Update analytics only after email is successfully sent
+		if err := database.UpdateAnalytics(q.db, int(job.UserID), job.Platform, true); err != nil {
+			log.Printf("Failed to update analytics for %s: %v", job.SentTo, err)
+		}
+		return
+	}
+
+	job.LastError = err.Error()
+	var perm permanentError
+	if errors.As(err, &perm) || job.Attempts >= job.MaxAttempts {
+		job.Status = models.EmailJobFailed
+		log.Printf("Email job %d failed after %d attempts: %v", job.ID, job.Attempts, err)
+	} else {
+		job.Status = models.EmailJobQueued
+		job.NextAttemptAt = time.Now().Add(time.Duration(1<<job.Attempts) * time.Minute)
+		log.Printf("Email job %d attempt %d failed, retrying at %s: %v", job.ID, job.Attempts, job.NextAttemptAt.Format(time.RFC3339), err)
+	}
+	if err := q.db.Save(job).Error; err != nil {
+		log.Printf("Failed to save email job %d: %v", job.ID, err)
+	}
+}
+
+func (q *Queue) setStatus(job *models.EmailJob, status string) error {
+	job.Status = status
+	return q.db.Model(job).Update("status", status).Error
+}
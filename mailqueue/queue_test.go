@@ -0,0 +1,41 @@
+package mailqueue
+
+import (
+	"testing"
+
+	"aiapply/database/dbtest"
+	"aiapply/models"
+)
+
+func TestResumeFailsInterruptedSends(t *testing.T) {
+	db := dbtest.Open(t, &models.EmailJob{}, &models.FollowUp{})
+
+	verifying := models.EmailJob{Status: models.EmailJobVerifying}
+	sending := models.EmailJob{Status: models.EmailJobSending}
+	followUp := models.FollowUp{Status: models.FollowUpSending}
+	for _, row := range []any{&verifying, &sending, &followUp} {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := New(db, 1, nil).resume(); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewScheduler(db).resume(); err != nil {
+		t.Fatal(err)
+	}
+
+	db.First(&verifying, verifying.ID)
+	if verifying.Status != models.EmailJobQueued {
+		t.Errorf("verifying job is %q, want it queued again", verifying.Status)
+	}
+	db.First(&sending, sending.ID)
+	if sending.Status != models.EmailJobFailed || sending.LastError != interruptedSend {
+		t.Errorf("sending job is %q (%q), want it failed", sending.Status, sending.LastError)
+	}
+	db.First(&followUp, followUp.ID)
+	if followUp.Status != models.FollowUpFailed || followUp.LastError != interruptedSend {
+		t.Errorf("sending follow-up is %q (%q), want it failed", followUp.Status, followUp.LastError)
+	}
+}
@@ -2,10 +2,19 @@ package main
 
 import (
 	"aiapply/database"
+	"aiapply/emailer"
 	"aiapply/handler"
+	"aiapply/inbox"
+	"aiapply/mailqueue"
 	"aiapply/middleware"
 	"aiapply/models"
+	"aiapply/scraper"
+	"context"
 	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -17,9 +26,32 @@ func main() {
 	if err != nil {
 		log.Fatal("Error loading .env file")
 	}
+	if err := emailer.CheckTracking(); err != nil {
+		log.Fatal(err)
+	}
 	database.InitDB()
 	db := database.DB
-	db.AutoMigrate(&models.User{}, &models.JobApplication{}, &models.Analytics{}, &models.PlatformBreakdown{}, &models.MonthlyStat{}, &models.ColdEmail{})
+	db.AutoMigrate(
+		&models.User{}, &models.JobApplication{}, &models.Analytics{}, &models.PlatformBreakdown{}, &models.MonthlyStat{}, &models.ColdEmail{},
+		&models.EmailJob{}, &models.EmailCandidate{}, &models.EmailEvent{}, &models.EmailReply{}, &models.MailSettings{}, &models.EmailTemplate{},
+		&models.Sequence{}, &models.SequenceStep{}, &models.FollowUp{}, &models.VerifiedAddress{}, &models.VerifiedDomain{}, &models.DomainPattern{}, &models.Job{}, &models.ScrapeRun{},
+	)
+
+	verifyCache := emailer.NewVerificationCache(db)
+
+	// Cold email workers; unfinished jobs from a previous run are resumed here
+	workers, _ := strconv.Atoi(os.Getenv("EMAIL_WORKERS"))
+	if err := mailqueue.New(db, workers, verifyCache).Start(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+	mailqueue.NewScheduler(db).Start(context.Background())
+	inbox.NewPoller(db, inbox.DialSettings).Start(context.Background())
+
+	// Selector overrides, reloaded when the file changes
+	selectorPath := os.Getenv("SCRAPER_SELECTORS")
+	if selectorPath != "" {
+		scraper.WatchSelectors(context.Background(), selectorPath, 30*time.Second)
+	}
 
 	r := gin.Default()
 
@@ -36,6 +68,10 @@ func main() {
 	r.POST("/register", handler.Register(db))
 	r.POST("/google", handler.GoogleLogin(db))
 
+	// Email tracking, hit by recipients' mail clients
+	r.GET("/t/open/:token", handler.TrackOpen(db))
+	r.GET("/t/click/:token", handler.TrackClick(db))
+
 	// Protected
 	api := r.Group("/api")
 	api.Use(middleware.JWTAuth())
@@ -43,6 +79,9 @@ func main() {
 	// Profile routes
 	api.GET("/profile", handler.GetProfile(db))
 	api.PUT("/profile", handler.UpdateProfile(db))
+	api.GET("/profile/mail", handler.GetMailSettings(db))
+	api.PUT("/profile/mail", handler.UpdateMailSettings(db))
+	api.POST("/profile/mail/test", handler.TestMailSettings(db))
 
 	// Analytics routes
 	api.GET("/analytics", handler.GetAnalytics(db))
@@ -53,12 +92,46 @@ func main() {
 	api.PUT("/users/:id", handler.UpdateUser(db))
 	api.DELETE("/users/:id", handler.DeleteUser(db))
 
+	// Template routes
+	api.GET("/templates", handler.ListTemplates(db))
+	api.POST("/templates", handler.CreateTemplate(db))
+	api.GET("/templates/:id", handler.GetTemplate(db))
+	api.PUT("/templates/:id", handler.UpdateTemplate(db))
+	api.DELETE("/templates/:id", handler.DeleteTemplate(db))
+	api.GET("/templates/:id/preview", handler.PreviewTemplate(db))
+
+	// Follow-up sequence routes
+	api.GET("/sequences", handler.ListSequences(db))
+	api.POST("/sequences", handler.CreateSequence(db))
+	api.GET("/sequences/:id", handler.GetSequence(db))
+	api.PUT("/sequences/:id", handler.UpdateSequence(db))
+	api.DELETE("/sequences/:id", handler.DeleteSequence(db))
+
 	// Scraper routes
+	api.POST("/scrape", handler.ScrapeUploads(db))
 	api.POST("/scrape/:platform", handler.ScrapeJobs(db))
+	api.POST("/scrape/:platform/fetch", handler.FetchJobs(db, scraper.NewFetcher()))
+	api.POST("/scrape/:platform/import", handler.ImportBoard(db, &http.Client{Timeout: 30 * time.Second}))
+	api.GET("/scrape/runs", handler.ListScrapeRuns(db))
+	api.GET("/jobs", handler.ListJobs(db))
+	api.GET("/selectors", handler.GetSelectors())
+	// the selector config is shared by all users
+	api.POST("/selectors/reload", middleware.RequireAdmin(), handler.ReloadSelectors(selectorPath))
+
+	// Email verification cache, shared by all users
+	verifications := api.Group("/verifications", middleware.RequireAdmin())
+	verifications.GET("/:address", handler.GetVerification(verifyCache))
+	verifications.DELETE("/:address", handler.InvalidateVerification(verifyCache))
+	verifications.DELETE("/domains/:domain", handler.InvalidateDomainVerification(verifyCache))
 
 	// Application routes
 	api.POST("/applications", middleware.JWTAuth(), handler.CreateApplication(db))
 	api.GET("/applications", middleware.JWTAuth(), handler.GetApplications(db))
+	api.PUT("/applications/:id", handler.UpdateApplication(db))
+	api.GET("/applications/:id/jobs", handler.GetApplicationJobs(db))
+	api.GET("/applications/:id/emails", handler.GetApplicationEmails(db))
+	api.GET("/applications/:id/followups", handler.GetApplicationFollowUps(db))
+	api.GET("/applications/:id/replies", handler.GetApplicationReplies(db))
 
 	log.Println("Starting HTTP server on :8090")
 	if err := r.Run(":8090"); err != nil {
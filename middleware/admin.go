@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin lets through only the users listed in ADMIN_USER_IDS, a comma
+// separated list of user IDs. It runs after JWTAuth. With no list, every
+// request is refused
+func RequireAdmin() gin.HandlerFunc {
+	admins := make(map[string]bool)
+	for _, id := range strings.Split(os.Getenv("ADMIN_USER_IDS"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			admins[id] = true
+		}
+	}
+
+	return func(c *gin.Context) {
+		userID, _ := c.Get("userID")
+		id, _ := userID.(string)
+		if !admins[id] {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -28,3 +28,14 @@ type MonthlyStat struct {
 	Applications int64     `json:"applications"`
 	Emails       int64     `json:"emails"`
 }
+
+// EngagementStats summarises how recipients reacted to sent cold emails
+type EngagementStats struct {
+	Sent      int64   `json:"sent"`
+	Opened    int64   `json:"opened"`
+	Clicked   int64   `json:"clicked"`
+	Replied   int64   `json:"replied"`
+	OpenRate  float64 `json:"open_rate"`
+	ClickRate float64 `json:"click_rate"`
+	ReplyRate float64 `json:"reply_rate"`
+}
@@ -11,6 +11,9 @@ type JobApplication struct {
 	DateApplied     time.Time `json:"date_applied"`
 	Status          string    `json:"status"` // e.g., Applied, Interviewing, Offer, Rejected
 	ApplicationType string    `json:"application_type"`
+	TemplateID      *uint     `json:"template_id"`
+	SequenceID      *uint     `json:"sequence_id"`
 	EmployeeNames   []string  `json:"employee_names" gorm:"-"`
+	JobID           *uint     `json:"job_id" gorm:"-"` // scraped job to take the title, company and hiring team from
 	Domain          string    `json:"domain" gorm:"-"`
 }
@@ -6,5 +6,5 @@ type ColdEmail struct {
 	gorm.Model
 	UserID      uint   `json:"user_id"`
 	ApplicationID uint   `json:"application_id"`
-	Status      string `json:"status"` // e.g., "sent", "opened", "replied"
+	Status      string `json:"status"` // "queued", "sent", "opened", "clicked", "replied"
 }
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// DomainPattern counts how often an address pattern proved right at a domain,
+// e.g. "f.last" at example.com.
+type DomainPattern struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Domain    string    `json:"domain" gorm:"uniqueIndex:idx_domain_pattern"`
+	Pattern   string    `json:"pattern" gorm:"uniqueIndex:idx_domain_pattern"`
+	Verified  int       `json:"verified"` // addresses that passed SMTP verification
+	Replied   int       `json:"replied"`  // addresses that answered a cold email
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
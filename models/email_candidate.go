@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Send results of an email candidate.
+const (
+	CandidateNotChecked = "not_checked"
+	CandidateSent       = "sent"
+	CandidateSendFailed = "failed"
+)
+
+// EmailCandidate is one address generated for an employee, with the outcome
+// of verifying it and, for the chosen one, of sending to it.
+type EmailCandidate struct {
+	ID                uint       `json:"id" gorm:"primaryKey"`
+	EmailJobID        uint       `json:"email_job_id" gorm:"uniqueIndex:idx_candidate_job_address"`
+	UserID            uint       `json:"user_id" gorm:"index"`
+	ApplicationID     uint       `json:"application_id" gorm:"index"`
+	EmployeeName      string     `json:"employee_name"`
+	Address           string     `json:"address" gorm:"uniqueIndex:idx_candidate_job_address"`
+	Pattern           string     `json:"pattern"`
+	Verification      string     `json:"verification"` // not_checked, valid, invalid, risky, error
+	VerificationError string     `json:"verification_error"`
+	SMTPCode          int        `json:"smtp_code"`
+	SendResult        string     `json:"send_result"` // sent, failed, or empty when not attempted
+	SendError         string     `json:"send_error"`
+	MessageID         string     `json:"message_id"`
+	CheckedAt         *time.Time `json:"checked_at"`
+	SentAt            *time.Time `json:"sent_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
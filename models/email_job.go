@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Email job states, in the order a job normally moves through them.
+const (
+	EmailJobQueued    = "queued"
+	EmailJobVerifying = "verifying"
+	EmailJobSending   = "sending"
+	EmailJobSent      = "sent"
+	EmailJobFailed    = "failed"
+)
+
+// EmailJob is one outbound cold email waiting to be verified and sent to a
+// single employee of the company behind an application.
+type EmailJob struct {
+	gorm.Model
+	UserID        uint       `json:"user_id" gorm:"index"`
+	ApplicationID uint       `json:"application_id" gorm:"index"`
+	EmployeeName  string     `json:"employee_name"`
+	Domain        string     `json:"domain"`
+	JobTitle      string     `json:"job_title"`
+	CompanyName   string     `json:"company_name"`
+	TemplateID    *uint      `json:"template_id"`
+	SequenceID    *uint      `json:"sequence_id"`
+	Platform      string     `json:"platform"`
+	Status        string     `json:"status" gorm:"index"` // queued, verifying, sending, sent, failed
+	Attempts      int        `json:"attempts"`
+	MaxAttempts   int        `json:"max_attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"index"`
+	LastError     string     `json:"last_error"`
+	SentTo        string     `json:"sent_to"`
+	MessageID     string     `json:"message_id"`
+	Subject       string     `json:"subject"`
+	SentAt        *time.Time `json:"sent_at"`
+	TrackingToken string     `json:"-" gorm:"index"`
+	OpenedAt      *time.Time `json:"opened_at"`
+	ClickedAt     *time.Time `json:"clicked_at"`
+	RepliedAt     *time.Time `json:"replied_at"`
+}
+
+// Email event types recorded by the tracking endpoints.
+const (
+	EmailEventOpen  = "open"
+	EmailEventClick = "click"
+)
+
+// EmailEvent is one open or click of a tracked cold email.
+type EmailEvent struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	EmailJobID    uint      `json:"email_job_id" gorm:"index"`
+	UserID        uint      `json:"user_id" gorm:"index"`
+	ApplicationID uint      `json:"application_id" gorm:"index"`
+	Type          string    `json:"type"` // open, click
+	URL           string    `json:"url"`
+	IP            string    `json:"ip"`
+	UserAgent     string    `json:"user_agent"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// EmailReply is an incoming message matched to a sent cold email.
+type EmailReply struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	UserID        uint      `json:"user_id" gorm:"index"`
+	ApplicationID uint      `json:"application_id" gorm:"index"`
+	EmailJobID    uint      `json:"email_job_id" gorm:"index"`
+	MessageID     string    `json:"message_id" gorm:"index"`
+	From          string    `json:"from"`
+	Subject       string    `json:"subject"`
+	Snippet       string    `json:"snippet"`
+	ReceivedAt    time.Time `json:"received_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "gorm.io/gorm"
+
+// EmailTemplate is a user's own cold email copy. Subject and PlainBody are Go
+// text/template sources, HTMLBody is an html/template source.
+type EmailTemplate struct {
+	gorm.Model
+	UserID    uint   `json:"user_id" gorm:"index"`
+	Name      string `json:"name"`
+	Subject   string `json:"subject"`
+	PlainBody string `json:"plain_body"`
+	HTMLBody  string `json:"html_body"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Job is a scraped listing, stored once per platform and external ID no
+// matter how often it is uploaded.
+type Job struct {
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	Platform   string `json:"platform" gorm:"uniqueIndex:idx_job_platform_external"`
+	ExternalID string `json:"external_id" gorm:"uniqueIndex:idx_job_platform_external"`
+	Role       string `json:"role"`
+	Company    string `json:"company" gorm:"index"`
+	Location   string `json:"location"`
+	Salary     string `json:"salary"`
+
+	// Salary parsed into comparable parts; amounts are per SalaryPeriod
+	SalaryMin      *float64 `json:"salary_min"`
+	SalaryMax      *float64 `json:"salary_max"`
+	SalaryCurrency string   `json:"salary_currency"`
+	SalaryPeriod   string   `json:"salary_period"` // hour, week, month or year
+	EquityMin      *float64 `json:"equity_min"`    // percent
+	EquityMax      *float64 `json:"equity_max"`    // percent
+	Stipend        bool     `json:"stipend"`
+
+	// Location parsed into places; Cities lists every canonical city and metro
+	City         string   `json:"city"`
+	Region       string   `json:"region"`
+	Country      string   `json:"country"`
+	RemotePolicy string   `json:"remote_policy"` // remote, hybrid, onsite or empty
+	Cities       []string `json:"cities" gorm:"type:jsonb;serializer:json"`
+
+	URL         string            `json:"url"`
+	Skills      []string          `json:"skills" gorm:"type:jsonb;serializer:json"`
+	PostedAt    *time.Time        `json:"posted_at"`
+	ApplyBy     *time.Time        `json:"apply_by" gorm:"index"`
+	Extras      map[string]string `json:"extras" gorm:"type:jsonb;serializer:json"`
+	FirstSeenAt time.Time         `json:"first_seen_at"`
+	LastSeenAt  time.Time         `json:"last_seen_at" gorm:"index"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
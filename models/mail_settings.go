@@ -0,0 +1,23 @@
+package models
+
+import "gorm.io/gorm"
+
+// MailSettings holds the mail servers a user sends cold emails through and
+// receives replies on. The IMAP login reuses Username and Password.
+type MailSettings struct {
+	gorm.Model
+	UserID      uint   `json:"user_id" gorm:"uniqueIndex"`
+	Host        string `json:"host"`
+	Port        int    `json:"port"`
+	FromAddress string `json:"from_address"`
+	FromName    string `json:"from_name"`
+	Username    string `json:"username"`
+	AuthMethod  string `json:"auth_method"` // e.g., "plain", "login", "cram-md5", "none"
+	Password    string `json:"-"`           // encrypted with utils.EncryptSecret
+
+	IMAPHost           string `json:"imap_host"`
+	IMAPPort           int    `json:"imap_port"`
+	IMAPAllowPlaintext bool   `json:"imap_allow_plaintext"` // log in without TLS when the server offers none
+	IMAPUIDValidity    uint32 `json:"-"`
+	IMAPLastUID        uint32 `json:"-"` // highest inbox UID already checked for replies
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// ScrapeRun records how well one upload or fetch was parsed, so parser
+// health can be followed over time.
+type ScrapeRun struct {
+	ID              uint               `json:"id" gorm:"primaryKey"`
+	Platform        string             `json:"platform" gorm:"index"`
+	Source          string             `json:"source"` // upload or fetch
+	SelectorVersion int                `json:"selector_version"`
+	Cards           int                `json:"cards"`
+	Jobs            int                `json:"jobs"`
+	FillRates       map[string]float64 `json:"fill_rates" gorm:"type:jsonb;serializer:json"`
+	Warnings        []string           `json:"warnings" gorm:"type:jsonb;serializer:json"`
+	Error           string             `json:"error"`
+	CreatedAt       time.Time          `json:"created_at" gorm:"index"`
+}
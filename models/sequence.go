@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Follow-up states.
+const (
+	FollowUpPending   = "pending"
+	FollowUpSending   = "sending"
+	FollowUpSent      = "sent"
+	FollowUpCancelled = "cancelled"
+	FollowUpFailed    = "failed"
+)
+
+// Sequence is a user's plan of follow-ups sent after an unanswered cold email.
+type Sequence struct {
+	gorm.Model
+	UserID uint           `json:"user_id" gorm:"index"`
+	Name   string         `json:"name"`
+	Steps  []SequenceStep `json:"steps" gorm:"foreignKey:SequenceID;constraint:OnDelete:CASCADE"`
+}
+
+// SequenceStep is one follow-up, sent DelayDays after the original email.
+type SequenceStep struct {
+	ID         uint  `json:"id" gorm:"primaryKey"`
+	SequenceID uint  `json:"sequence_id" gorm:"index"`
+	Position   int   `json:"position"`
+	DelayDays  int   `json:"delay_days"`
+	TemplateID *uint `json:"template_id"`
+}
+
+// FollowUp is a scheduled step of a sequence for one sent cold email.
+type FollowUp struct {
+	gorm.Model
+	UserID        uint       `json:"user_id" gorm:"index"`
+	ApplicationID uint       `json:"application_id" gorm:"index"`
+	EmailJobID    uint       `json:"email_job_id" gorm:"index"`
+	Step          int        `json:"step"`
+	TemplateID    *uint      `json:"template_id"`
+	DueAt         time.Time  `json:"due_at" gorm:"index"` // pushed back when a send is retried
+	Status        string     `json:"status" gorm:"index"` // pending, sending, sent, cancelled, failed
+	Attempts      int        `json:"attempts"`
+	MessageID     string     `json:"message_id"`
+	SentAt        *time.Time `json:"sent_at"`
+	LastError     string     `json:"last_error"`
+}
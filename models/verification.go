@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// VerifiedAddress caches the outcome of verifying one email address.
+type VerifiedAddress struct {
+	Address   string    `json:"address" gorm:"primaryKey"`
+	Result    string    `json:"result"` // valid, invalid or risky
+	Reason    string    `json:"reason"`
+	MXHost    string    `json:"mx_host"`
+	SMTPCode  int       `json:"smtp_code"`
+	CheckedAt time.Time `json:"checked_at"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"index"`
+}
+
+// VerifiedDomain caches what is known about the mail servers of a domain.
+type VerifiedDomain struct {
+	Domain    string    `json:"domain" gorm:"primaryKey"`
+	MXHosts   string    `json:"mx_hosts"`  // comma separated, most preferred first; empty when the domain has none
+	CatchAll  *bool     `json:"catch_all"` // nil until the domain has been probed
+	CheckedAt time.Time `json:"checked_at"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"index"`
+}
@@ -0,0 +1,71 @@
+package naukri
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"aiapply/scraper"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// JobDetail holds the fields of one Naukri search result.
+type JobDetail struct {
+	ID          string
+	Role        string
+	Company     string
+	URL         string
+	Experience  string // e.g. "4-8 Yrs"
+	Salary      string // empty when "Not disclosed"
+	Location    string
+	Description string
+	Skills      []string
+	PostedAgo   string // e.g. "3 Days Ago", "Just Now"
+	Rating      string // AmbitionBox rating, e.g. "3.7"
+}
+
+// ScrapeJobs reads a saved Naukri search results page.
+func ScrapeJobs(reader io.Reader) ([]JobDetail, error) {
+	doc, err := goquery.NewDocumentFromReader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse HTML: %w", err)
+	}
+	sel := scraper.SelectorsFor("naukri")
+
+	var jobs []JobDetail
+	sel.Find(doc.Selection, "card").Each(func(_ int, s *goquery.Selection) {
+		id, _ := s.Attr("data-job-id")
+		job := JobDetail{
+			ID:          id,
+			Role:        sel.Text(s, "role"),
+			Company:     sel.Text(s, "company"),
+			URL:         sel.Attr(s, "job_link", "href"),
+			Experience:  sel.Text(s, "experience"),
+			Salary:      sel.Text(s, "salary"),
+			Location:    sel.Text(s, "location"),
+			Description: sel.Text(s, "description"),
+			PostedAgo:   sel.Text(s, "posted_ago"),
+			Rating:      sel.Text(s, "rating"),
+		}
+		if strings.EqualFold(job.Salary, "Not disclosed") {
+			job.Salary = ""
+		}
+		sel.Find(s, "skill").Each(func(_ int, skill *goquery.Selection) {
+			if text := strings.TrimSpace(skill.Text()); text != "" {
+				job.Skills = append(job.Skills, text)
+			}
+		})
+		if job.ID == "" {
+			// the job ID ends the listing URL
+			if i := strings.LastIndex(job.URL, "-"); i >= 0 {
+				job.ID = strings.TrimSpace(job.URL[i+1:])
+			}
+		}
+		if job.ID != "" || job.Role != "" {
+			jobs = append(jobs, job)
+		}
+	})
+
+	return jobs, nil
+}
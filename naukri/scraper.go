@@ -0,0 +1,68 @@
+package naukri
+
+import (
+	"io"
+
+	"aiapply/scraper"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func init() {
+	scraper.Register(Scraper{})
+}
+
+// Scraper maps Naukri search results into scraper jobs.
+type Scraper struct{}
+
+func (Scraper) Platform() string { return "naukri" }
+
+func (Scraper) Fingerprint() scraper.Fingerprint {
+	return scraper.Fingerprint{
+		Hosts:     []string{"naukri.com"},
+		SiteNames: []string{"Naukri.com", "Naukri"},
+		Markers:   []string{"card"},
+		Strings:   []string{"naukri.com/"},
+	}
+}
+
+// FetchHints follows the numbered pages below the results.
+func (Scraper) FetchHints() scraper.FetchHints {
+	sel := scraper.SelectorsFor("naukri")
+	return scraper.FetchHints{Card: sel.Group("card"), Next: sel.Group("next_page")}
+}
+
+func (Scraper) CountCards(r io.Reader) (int, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return 0, err
+	}
+	return scraper.SelectorsFor("naukri").Find(doc.Selection, "card").Length(), nil
+}
+
+func (Scraper) Scrape(r io.Reader) ([]scraper.Job, error) {
+	details, err := ScrapeJobs(r)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]scraper.Job, 0, len(details))
+	for _, d := range details {
+		job := scraper.Job{
+			ID:       d.ID,
+			Platform: "naukri",
+			Role:     d.Role,
+			Company:  d.Company,
+			Location: d.Location,
+			Salary:   d.Salary,
+			URL:      d.URL,
+			Skills:   d.Skills,
+		}
+		job.SetExtra("experience", d.Experience)
+		job.SetExtra("description", d.Description)
+		job.SetExtra("posted_ago", d.PostedAgo)
+		job.SetExtra("rating", d.Rating)
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,54 @@
+package naukri
+
+import (
+	"testing"
+	"time"
+
+	"aiapply/scraper"
+	"aiapply/scraper/scrapertest"
+)
+
+const day = 24 * time.Hour
+
+func TestScrapeFixture(t *testing.T) {
+	lacs := func(min, max float64) *scraper.Compensation {
+		return &scraper.Compensation{Min: min * 100000, Max: max * 100000, Currency: "INR", Period: "year"}
+	}
+	scrapertest.CheckFixture(t, Scraper{}, "naukri.html", []scrapertest.Want{
+		{
+			ID: "090725500123", Role: "Senior Golang Developer", Company: "Razorpay", Location: "Bengaluru", Salary: "25-40 Lacs PA",
+			URL:    "https://www.naukri.com/job-listings-senior-golang-developer-razorpay-bengaluru-4-to-8-years-090725500123",
+			Extras: map[string]string{"experience": "4-8 Yrs", "posted_ago": "3 Days Ago", "rating": "3.7"},
+			Age:    3 * day, Compensation: lacs(25, 40), City: "Bengaluru",
+		},
+		{
+			// no salary disclosed; hybrid across two cities
+			ID: "100725907781", Role: "Backend Engineer - Go", Company: "Zeta Suite", Location: "Hybrid - Bengaluru, Hyderabad",
+			URL:    "https://www.naukri.com/job-listings-backend-engineer-go-zeta-suite-hybrid-bengaluru-hyderabad-2-to-5-years-100725907781",
+			Extras: map[string]string{"experience": "2-5 Yrs", "posted_ago": "Just Now"},
+			Policy: "hybrid", City: "Bengaluru",
+		},
+		{
+			ID: "080725011456", Role: "Software Engineer II (Golang)", Company: "Freshworks", Location: "Remote", Salary: "18-30 Lacs PA",
+			URL:    "https://www.naukri.com/job-listings-software-engineer-ii-golang-freshworks-remote-3-to-6-years-080725011456",
+			Extras: map[string]string{"experience": "3-6 Yrs", "posted_ago": "30+ Days Ago"},
+			Age:    30 * day, Compensation: lacs(18, 30), Policy: "remote",
+		},
+		{
+			ID: "110725004410", Role: "Go Developer", Company: "Techversant Infotech", Location: "Kochi, Bengaluru, Gurugram", Salary: "4-8 Lacs PA",
+			URL:    "https://www.naukri.com/job-listings-go-developer-techversant-infotech-kochi-bengaluru-gurugram-1-to-3-years-110725004410",
+			Extras: map[string]string{"experience": "1-3 Yrs", "posted_ago": "1 Day Ago"},
+			Age:    day, Compensation: lacs(4, 8), City: "Kochi",
+		},
+		{
+			ID: "070725920034", Role: "Platform Engineer", Company: "Meesho", Location: "Bangalore/Bengaluru", Salary: "35-55 Lacs PA",
+			URL:    "https://www.naukri.com/job-listings-platform-engineer-meesho-bengaluru-5-to-9-years-070725920034",
+			Extras: map[string]string{"experience": "5-9 Yrs", "posted_ago": "5 Days Ago"},
+			Age:    5 * day, Compensation: lacs(35, 55), City: "Bengaluru",
+		},
+	})
+}
+
+func TestCountCards(t *testing.T) {
+	scrapertest.CheckCardCount(t, Scraper{}, "naukri.html", 5)
+}
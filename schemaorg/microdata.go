@@ -0,0 +1,75 @@
+package schemaorg
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// microdataItem reads the properties of an itemscope element into the shape
+// JSON-LD decodes to, so both are mapped the same way. Nested items become
+// nested objects and repeated properties lists.
+func microdataItem(item *goquery.Selection) map[string]any {
+	props := map[string]any{"@type": typeName(item.AttrOr("itemtype", ""))}
+
+	var walk func(s *goquery.Selection)
+	walk = func(s *goquery.Selection) {
+		s.Children().Each(func(_ int, child *goquery.Selection) {
+			_, scoped := child.Attr("itemscope")
+			if names := strings.Fields(child.AttrOr("itemprop", "")); len(names) > 0 {
+				var value any
+				if scoped {
+					value = microdataItem(child)
+				} else {
+					value = microdataValue(child)
+				}
+				for _, name := range names {
+					addProperty(props, name, value)
+				}
+			}
+			// properties below a nested item belong to it
+			if !scoped {
+				walk(child)
+			}
+		})
+	}
+	walk(item)
+	return props
+}
+
+// microdataValue is the value of a property element as the microdata spec
+// defines it for its tag.
+func microdataValue(s *goquery.Selection) string {
+	switch goquery.NodeName(s) {
+	case "meta":
+		return strings.TrimSpace(s.AttrOr("content", ""))
+	case "a", "area", "link":
+		return strings.TrimSpace(s.AttrOr("href", ""))
+	case "img", "audio", "video", "source", "iframe", "embed", "track":
+		return strings.TrimSpace(s.AttrOr("src", ""))
+	case "object":
+		return strings.TrimSpace(s.AttrOr("data", ""))
+	case "data", "meter":
+		return strings.TrimSpace(s.AttrOr("value", ""))
+	case "time":
+		if datetime, ok := s.Attr("datetime"); ok {
+			return strings.TrimSpace(datetime)
+		}
+	}
+	// a content attribute on other tags is a common slip; it is still meant
+	if content, ok := s.Attr("content"); ok {
+		return strings.TrimSpace(content)
+	}
+	return strings.Join(strings.Fields(s.Text()), " ")
+}
+
+func addProperty(props map[string]any, name string, value any) {
+	switch existing := props[name].(type) {
+	case nil:
+		props[name] = value
+	case []any:
+		props[name] = append(existing, value)
+	default:
+		props[name] = []any{existing, value}
+	}
+}
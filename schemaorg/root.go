@@ -0,0 +1,474 @@
+package schemaorg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"aiapply/scraper"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// JobDetail holds the fields of one schema.org JobPosting.
+type JobDetail struct {
+	Identifier     string
+	Role           string
+	Company        string
+	CompanyURL     string
+	CompanyLogo    string
+	URL            string
+	Location       string // places joined with " • "
+	Remote         bool   // jobLocationType TELECOMMUTE
+	Salary         string // e.g. "INR 1,200,000 - 1,800,000 per year"
+	Compensation   *scraper.Compensation
+	EmploymentType string // e.g. "Full-time, Contract"
+	Description    string
+	Skills         []string
+	PostedAt       *time.Time
+	ValidThrough   *time.Time
+}
+
+// ScrapePostings reads the JobPosting items of a saved page, from JSON-LD
+// scripts as well as microdata. A JSON-LD document on its own is read too.
+// Postings without a title are left out.
+func ScrapePostings(reader io.Reader) ([]JobDetail, error) {
+	all, err := readPostings(reader)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]JobDetail, 0, len(all))
+	for _, job := range all {
+		if job.Role != "" {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+// readPostings returns every posting found, once, titled or not.
+func readPostings(reader io.Reader) ([]JobDetail, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []map[string]any
+	var page string
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		var doc any
+		if err := json.Unmarshal(trimmed, &doc); err != nil {
+			return nil, fmt.Errorf("could not parse JSON-LD: %w", err)
+		}
+		items = findPostings(doc)
+	} else {
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse HTML: %w", err)
+		}
+		page = pageURL(doc)
+		doc.Find("script[type='application/ld+json']").Each(func(_ int, s *goquery.Selection) {
+			var ld any
+			// careers sites often leave raw line breaks inside strings
+			if err := json.Unmarshal([]byte(controlChars.Replace(s.Text())), &ld); err == nil {
+				items = append(items, findPostings(ld)...)
+			}
+		})
+		doc.Find("[itemscope][itemtype]").Each(func(_ int, s *goquery.Selection) {
+			if _, nested := s.Attr("itemprop"); !nested && typeName(s.AttrOr("itemtype", "")) == "JobPosting" {
+				items = append(items, microdataItem(s))
+			}
+		})
+	}
+
+	seen := make(map[string]bool)
+	var jobs []JobDetail
+	for _, item := range items {
+		job := jobDetail(item, page)
+		// pages often carry the same posting as JSON-LD and microdata
+		key := strings.ToLower(job.Role + "|" + job.Company + "|" + job.Location)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		jobs = append(jobs, job)
+	}
+
+	// a lone posting without its own link is the page itself
+	if len(jobs) == 1 && jobs[0].URL == "" {
+		jobs[0].URL = page
+	}
+	return jobs, nil
+}
+
+var controlChars = strings.NewReplacer("\n", " ", "\r", " ", "\t", " ")
+
+// findPostings walks a JSON-LD document for JobPosting objects, including
+// the ones in an @graph or an ItemList.
+func findPostings(v any) []map[string]any {
+	switch v := v.(type) {
+	case []any:
+		var items []map[string]any
+		for _, e := range v {
+			items = append(items, findPostings(e)...)
+		}
+		return items
+	case map[string]any:
+		if isType(v, "JobPosting") {
+			return []map[string]any{v}
+		}
+		var items []map[string]any
+		for _, e := range v {
+			items = append(items, findPostings(e)...)
+		}
+		return items
+	}
+	return nil
+}
+
+func jobDetail(item map[string]any, page string) JobDetail {
+	job := JobDetail{
+		Identifier:     identifier(item["identifier"]),
+		Role:           text(item["title"]),
+		URL:            resolve(page, text(item["url"])),
+		Location:       location(item),
+		Remote:         strings.EqualFold(text(item["jobLocationType"]), "TELECOMMUTE"),
+		EmploymentType: employmentType(item["employmentType"]),
+		Description:    scraper.PlainText(text(item["description"])),
+		Skills:         skills(item["skills"]),
+		PostedAt:       date(text(item["datePosted"])),
+		ValidThrough:   deadline(text(item["validThrough"])),
+	}
+	if job.Role == "" {
+		job.Role = text(item["name"])
+	}
+
+	switch org := first(item["hiringOrganization"]).(type) {
+	case map[string]any:
+		job.Company = text(org["name"])
+		job.CompanyURL = resolve(page, text(org["sameAs"]))
+		if job.CompanyURL == "" {
+			job.CompanyURL = resolve(page, text(org["url"]))
+		}
+		if logo, ok := first(org["logo"]).(map[string]any); ok {
+			job.CompanyLogo = resolve(page, text(logo["url"]))
+		} else {
+			job.CompanyLogo = resolve(page, text(org["logo"]))
+		}
+	case string:
+		job.Company = strings.TrimSpace(org)
+	}
+
+	salary := item["baseSalary"]
+	if salary == nil {
+		salary = item["estimatedSalary"]
+	}
+	job.Salary, job.Compensation = baseSalary(salary)
+	return job
+}
+
+// location joins the postal addresses of jobLocation. Remote postings name
+// the countries they hire from in applicantLocationRequirements instead.
+func location(item map[string]any) string {
+	var places []string
+	add := func(place string) {
+		for _, p := range places {
+			if strings.EqualFold(p, place) {
+				return
+			}
+		}
+		if place != "" {
+			places = append(places, place)
+		}
+	}
+
+	for _, loc := range list(item["jobLocation"]) {
+		place, ok := loc.(map[string]any)
+		if !ok {
+			add(text(loc))
+			continue
+		}
+		switch address := first(place["address"]).(type) {
+		case map[string]any:
+			add(postalAddress(address))
+		case string:
+			add(strings.TrimSpace(address))
+		default:
+			add(text(place["name"]))
+		}
+	}
+	if len(places) == 0 {
+		for _, req := range list(item["applicantLocationRequirements"]) {
+			add(countryName(text(req)))
+		}
+	}
+	return strings.Join(places, " • ")
+}
+
+// postalAddress formats an address as "City, Region, Country"
+func postalAddress(address map[string]any) string {
+	var parts []string
+	for _, part := range []string{
+		text(address["addressLocality"]),
+		text(address["addressRegion"]),
+		countryName(text(address["addressCountry"])),
+	} {
+		// city states repeat themselves, e.g. "Singapore, Singapore"
+		if part != "" && (len(parts) == 0 || !strings.EqualFold(parts[len(parts)-1], part)) {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// countryCodes holds the ISO 3166 codes careers sites use most; addresses
+// give the code more often than the name.
+var countryCodes = map[string]string{
+	"IN": "India", "US": "United States", "GB": "United Kingdom", "UK": "United Kingdom",
+	"CA": "Canada", "AU": "Australia", "DE": "Germany", "FR": "France", "NL": "Netherlands",
+	"IE": "Ireland", "SG": "Singapore", "AE": "United Arab Emirates", "PH": "Philippines",
+	"ID": "Indonesia", "MY": "Malaysia", "JP": "Japan", "BR": "Brazil", "MX": "Mexico",
+	"ES": "Spain", "PL": "Poland", "PT": "Portugal", "IL": "Israel",
+}
+
+func countryName(country string) string {
+	if name, ok := countryCodes[strings.ToUpper(country)]; ok && len(country) == 2 {
+		return name
+	}
+	return country
+}
+
+var employmentTypes = map[string]string{
+	"FULL_TIME": "Full-time", "PART_TIME": "Part-time", "CONTRACTOR": "Contract",
+	"TEMPORARY": "Temporary", "INTERN": "Internship", "VOLUNTEER": "Volunteer",
+	"PER_DIEM": "Per diem", "OTHER": "Other",
+}
+
+func employmentType(v any) string {
+	var types []string
+	for _, e := range list(v) {
+		// some sites put several types in one comma separated string
+		for _, t := range strings.Split(text(e), ",") {
+			t = strings.TrimSpace(t)
+			if name, ok := employmentTypes[strings.ToUpper(strings.ReplaceAll(t, "-", "_"))]; ok {
+				t = name
+			}
+			if t != "" {
+				types = append(types, t)
+			}
+		}
+	}
+	return strings.Join(types, ", ")
+}
+
+var salaryPeriods = map[string]string{
+	"HOUR": scraper.PeriodHour, "WEEK": scraper.PeriodWeek,
+	"MONTH": scraper.PeriodMonth, "YEAR": scraper.PeriodYear,
+}
+
+// baseSalary reads a MonetaryAmount. It returns the amount as text along
+// with its Compensation, which is nil when the period is not one we compare
+// (such as DAY) so Normalize falls back to parsing the text.
+func baseSalary(v any) (string, *scraper.Compensation) {
+	amount, ok := first(v).(map[string]any)
+	if !ok {
+		return text(v), nil
+	}
+	currency := strings.ToUpper(text(amount["currency"]))
+	unit := strings.ToUpper(text(amount["unitText"]))
+
+	var min, max float64
+	switch value := first(amount["value"]).(type) {
+	case map[string]any:
+		min, max = number(value["minValue"]), number(value["maxValue"])
+		if v := number(value["value"]); v > 0 && min == 0 && max == 0 {
+			min, max = v, v
+		}
+		if u := text(value["unitText"]); u != "" {
+			unit = strings.ToUpper(u)
+		}
+		if currency == "" {
+			currency = strings.ToUpper(text(value["currency"]))
+		}
+	default:
+		min = number(value)
+		max = min
+	}
+	if min == 0 && max == 0 {
+		return "", nil
+	}
+	if max == 0 {
+		max = min
+	}
+	if min == 0 {
+		min = max
+	}
+
+	salary := scraper.FormatAmount(min)
+	if max != min {
+		salary += " - " + scraper.FormatAmount(max)
+	}
+	if currency != "" {
+		salary = currency + " " + salary
+	}
+	if unit != "" {
+		salary += " per " + strings.ToLower(unit)
+	}
+
+	period, ok := salaryPeriods[unit]
+	if !ok {
+		return salary, nil
+	}
+	return salary, &scraper.Compensation{Min: min, Max: max, Currency: currency, Period: period}
+}
+
+// identifier reads a PropertyValue, whose name is the issuer rather than
+// the ID.
+func identifier(v any) string {
+	if id, ok := first(v).(map[string]any); ok {
+		return text(id["value"])
+	}
+	return text(v)
+}
+
+func skills(v any) []string {
+	var out []string
+	for _, e := range list(v) {
+		for _, skill := range strings.Split(text(e), ",") {
+			if skill = strings.TrimSpace(skill); skill != "" {
+				out = append(out, skill)
+			}
+		}
+	}
+	return out
+}
+
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// date parses the ISO 8601 dates of datePosted and validThrough.
+func date(value string) *time.Time {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// deadline parses validThrough like date. A date without a time of day
+// stays open until the end of that day.
+func deadline(value string) *time.Time {
+	t := date(value)
+	if t != nil && len(value) == len(time.DateOnly) {
+		end := time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, t.Location())
+		return &end
+	}
+	return t
+}
+
+// pageURL is the address a saved page names itself by.
+func pageURL(doc *goquery.Document) string {
+	if href := strings.TrimSpace(doc.Find("link[rel='canonical']").AttrOr("href", "")); href != "" {
+		return href
+	}
+	return strings.TrimSpace(doc.Find("meta[property='og:url']").AttrOr("content", ""))
+}
+
+// resolve makes ref absolute against the page it was found on.
+func resolve(page, ref string) string {
+	if ref == "" || page == "" {
+		return ref
+	}
+	base, err := url.Parse(page)
+	if err != nil {
+		return ref
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
+
+// isType reports whether a JSON-LD object's @type, a name or a list of
+// names, includes name.
+func isType(item map[string]any, name string) bool {
+	for _, t := range list(item["@type"]) {
+		if s, ok := t.(string); ok && typeName(s) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// typeName drops the vocabulary from a type such as
+// "https://schema.org/JobPosting".
+func typeName(t string) string {
+	t = strings.TrimSpace(t)
+	if i := strings.IndexAny(t, " \t\n"); i >= 0 {
+		t = t[:i]
+	}
+	return t[strings.LastIndexAny(t, "/#:")+1:]
+}
+
+// list returns v as a slice, wrapping single values.
+func list(v any) []any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []any:
+		return v
+	}
+	return []any{v}
+}
+
+func first(v any) any {
+	if l := list(v); len(l) > 0 {
+		return l[0]
+	}
+	return nil
+}
+
+// text reads a JSON-LD value as text. Objects give their name or value,
+// such as an Organization's name or a PropertyValue's value.
+func text(v any) string {
+	switch v := first(v).(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]any:
+		for _, key := range []string{"name", "value", "@value", "@id"} {
+			if s := text(v[key]); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// number reads an amount given as a number or as text such as "1,20,000".
+func number(v any) float64 {
+	switch v := first(v).(type) {
+	case float64:
+		return v
+	case string:
+		n, _ := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(v), ",", ""), 64)
+		return n
+	case map[string]any:
+		return number(v["value"])
+	}
+	return 0
+}
@@ -0,0 +1,68 @@
+package schemaorg
+
+import (
+	"io"
+	"time"
+
+	"aiapply/scraper"
+)
+
+func init() {
+	scraper.Register(Scraper{})
+}
+
+// Scraper maps the schema.org JobPosting data of any careers page or job
+// board into scraper jobs.
+type Scraper struct{}
+
+func (Scraper) Platform() string { return "schemaorg" }
+
+// Fingerprint only names the type; pages of a platform we have a scraper
+// for score higher on their own markup even when they embed JobPostings.
+func (Scraper) Fingerprint() scraper.Fingerprint {
+	return scraper.Fingerprint{
+		Strings: []string{"JobPosting"},
+	}
+}
+
+// CountCards counts the postings on the page, including untitled ones.
+func (Scraper) CountCards(r io.Reader) (int, error) {
+	postings, err := readPostings(r)
+	return len(postings), err
+}
+
+func (Scraper) Scrape(r io.Reader) ([]scraper.Job, error) {
+	details, err := ScrapePostings(r)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]scraper.Job, 0, len(details))
+	for _, d := range details {
+		job := scraper.Job{
+			Platform:     "schemaorg",
+			Role:         d.Role,
+			Company:      d.Company,
+			Location:     d.Location,
+			Salary:       d.Salary,
+			URL:          d.URL,
+			Skills:       d.Skills,
+			PostedAt:     d.PostedAt,
+			Compensation: d.Compensation,
+		}
+		job.SetExtra("identifier", d.Identifier)
+		job.SetExtra("company_url", d.CompanyURL)
+		job.SetExtra("company_photo_url", d.CompanyLogo)
+		job.SetExtra("employment_type", d.EmploymentType)
+		job.SetExtra("description", d.Description)
+		if d.Remote {
+			job.SetExtra("mode", "Remote")
+		}
+		if d.ValidThrough != nil {
+			job.ApplyBy = d.ValidThrough
+			job.SetExtra("apply_by", d.ValidThrough.Format(time.DateOnly))
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,161 @@
+package schemaorg
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"aiapply/scraper"
+)
+
+func readFixture(t *testing.T) []JobDetail {
+	f, err := os.Open("careers.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	postings, err := ScrapePostings(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return postings
+}
+
+func TestScrapePostings(t *testing.T) {
+	ist := time.FixedZone("+0530", 5*3600+1800)
+	at := func(t time.Time) *time.Time { return &t }
+	want := []JobDetail{
+		// JSON-LD in an @graph; the microdata copy of it further down is left out
+		{
+			Identifier: "CB-1042", Role: "Senior Software Engineer, Billing", Company: "Chargebee",
+			CompanyURL: "https://www.chargebee.com", CompanyLogo: "https://www.chargebee.com/static/logo.png",
+			URL:            "https://careers.chargebee.com/jobs/cb-1042-senior-software-engineer-billing",
+			Location:       "Chennai, Tamil Nadu, India • Bengaluru, Karnataka, India",
+			Salary:         "INR 2,800,000 - 4,200,000 per year",
+			Compensation:   &scraper.Compensation{Min: 2800000, Max: 4200000, Currency: "INR", Period: "year"},
+			EmploymentType: "Full-time",
+			Description:    "Own the invoicing engine that bills thousands of SaaS businesses.\n5+ years building backend services",
+			Skills:         []string{"Go", "PostgreSQL", "Kafka"},
+			PostedAt:       at(time.Date(2024, time.July, 8, 0, 0, 0, 0, time.UTC)),
+			// a time of day is kept as given
+			ValidThrough: at(time.Date(2024, time.August, 12, 23, 59, 0, 0, time.UTC)),
+		},
+		// JSON-LD of a remote job: the organization is only a name
+		{
+			Role: "Developer Advocate", Company: "Chargebee",
+			URL:      "https://careers.chargebee.com/jobs/cb-1077-developer-advocate",
+			Location: "India", Remote: true,
+			Salary:         "USD 45 per hour",
+			Compensation:   &scraper.Compensation{Min: 45, Max: 45, Currency: "USD", Period: "hour"},
+			EmploymentType: "Full-time, Contract",
+			Description:    "Write guides, talk at meetups and help developers ship subscriptions faster.",
+			Skills:         []string{},
+			PostedAt:       at(time.Date(2024, time.July, 15, 9, 30, 0, 0, ist)),
+		},
+		// microdata
+		{
+			Role: "Product Design Intern", Company: "Chargebee", CompanyURL: "https://www.chargebee.com",
+			URL:            "https://careers.chargebee.com/jobs/cb-1091-product-design-intern",
+			Location:       "Chennai, India",
+			Salary:         "INR 40,000 per month",
+			Compensation:   &scraper.Compensation{Min: 40000, Max: 40000, Currency: "INR", Period: "month"},
+			EmploymentType: "Internship",
+			Description:    "Design checkout and billing flows with our product team for six months.",
+			Skills:         []string{},
+			PostedAt:       at(time.Date(2024, time.July, 20, 0, 0, 0, 0, time.UTC)),
+			// a date alone is open until the end of that day
+			ValidThrough: at(time.Date(2024, time.August, 5, 23, 59, 59, 0, time.UTC)),
+		},
+	}
+
+	got := readFixture(t)
+	if len(got) != len(want) {
+		t.Fatalf("got %d postings, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		if !sameTime(g.PostedAt, w.PostedAt) || !sameTime(g.ValidThrough, w.ValidThrough) {
+			t.Errorf("%s: posted %v, valid through %v; want %v, %v", w.Role, g.PostedAt, g.ValidThrough, w.PostedAt, w.ValidThrough)
+		}
+		g.PostedAt, g.ValidThrough, w.PostedAt, w.ValidThrough = nil, nil, nil, nil
+		if len(g.Skills) == 0 && len(w.Skills) == 0 {
+			g.Skills, w.Skills = nil, nil
+		}
+		if !reflect.DeepEqual(g, w) {
+			t.Errorf("posting %d =\n%+v\nwant\n%+v", i, g, w)
+		}
+	}
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func TestScrape(t *testing.T) {
+	f, err := os.Open("careers.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	jobs, err := Scraper{}.Scrape(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 3 {
+		t.Fatalf("got %d jobs, want 3", len(jobs))
+	}
+	billing, advocate, intern := jobs[0], jobs[1], jobs[2]
+	if billing.Extras["identifier"] != "CB-1042" || billing.Extras["apply_by"] != "2024-08-12" || billing.Extras["employment_type"] != "Full-time" {
+		t.Errorf("billing extras = %v", billing.Extras)
+	}
+	if advocate.Extras["mode"] != "Remote" || advocate.ApplyBy != nil {
+		t.Errorf("advocate: mode %q, apply by %v; want Remote and no deadline", advocate.Extras["mode"], advocate.ApplyBy)
+	}
+	if intern.Extras["apply_by"] != "2024-08-05" || intern.ApplyBy == nil || intern.ApplyBy.Hour() != 23 {
+		t.Errorf("intern: apply by %q, %v; want the end of 5 August", intern.Extras["apply_by"], intern.ApplyBy)
+	}
+
+	// the remote job and the internship after normalizing
+	scraper.Normalize(jobs)
+	if advocate := jobs[1]; advocate.Workplace == nil || advocate.Workplace.Policy != "remote" {
+		t.Errorf("advocate workplace = %+v, want remote", jobs[1].Workplace)
+	}
+	if intern := jobs[2]; intern.Compensation == nil || !intern.Compensation.Stipend {
+		t.Errorf("intern compensation = %+v, want a stipend", jobs[2].Compensation)
+	}
+}
+
+func TestCountCards(t *testing.T) {
+	f, err := os.Open("careers.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	// the three postings and the untitled talent pool
+	if n, err := (Scraper{}).CountCards(f); err != nil || n != 4 {
+		t.Errorf("CountCards = %d, %v; want 4", n, err)
+	}
+}
+
+func TestScrapeJSONLD(t *testing.T) {
+	doc := `{"@context": "https://schema.org", "@type": "JobPosting", "title": "SRE",
+		"hiringOrganization": {"name": "Acme"}, "jobLocationType": "TELECOMMUTE", "validThrough": "2025-03-01"}`
+	jobs, err := ScrapePostings(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].Role != "SRE" || jobs[0].Company != "Acme" || !jobs[0].Remote {
+		t.Fatalf("postings = %+v", jobs)
+	}
+	if want := time.Date(2025, time.March, 1, 23, 59, 59, 0, time.UTC); !sameTime(jobs[0].ValidThrough, &want) {
+		t.Errorf("valid through %v, want %v", jobs[0].ValidThrough, want)
+	}
+}
@@ -0,0 +1,73 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+// HTTPClient sends the requests of ImportBoard. *http.Client satisfies it;
+// tests can answer from a local stub instead of the network.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// BoardImporter is implemented by scrapers of ATS job boards that publish
+// their listings as JSON, such as Greenhouse and Lever.
+type BoardImporter interface {
+	Scraper
+	// BoardURL turns a board page, API link or bare board name into the
+	// address of the board's JSON listing.
+	BoardURL(ref string) (string, error)
+}
+
+// Largest board listing ImportBoard reads
+const maxBoardSize = 50 << 20
+
+// ImportBoard downloads the JSON listing of a job board and returns its
+// jobs, parsed by the scraper registered for platform.
+func ImportBoard(ctx context.Context, client HTTPClient, platform, ref string) ([]Job, error) {
+	s, _ := Get(platform)
+	importer, ok := s.(BoardImporter)
+	if !ok {
+		return nil, fmt.Errorf("%s boards cannot be imported", platform)
+	}
+	boardURL, err := importer.BoardURL(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, boardURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not load %s: %w", boardURL, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("no %s board at %s", platform, boardURL)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%s answered %s", boardURL, resp.Status)
+	}
+	return s.Scrape(io.LimitReader(resp.Body, maxBoardSize))
+}
+
+// BoardCompany guesses a company name from its board name, e.g. "acme-labs"
+// gives "Acme Labs". Boards that do not list the company use it.
+func BoardCompany(board string) string {
+	words := strings.FieldsFunc(board, func(r rune) bool { return r == '-' || r == '_' || r == '.' })
+	for i, word := range words {
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		words[i] = string(r)
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,269 @@
+package scraper
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Platforms print dates the way their readers speak: "3 days ago", "Posted
+// 2 weeks ago", "Just now", "Apply by 12 Aug", "12th August 2024", and on
+// localized LinkedIn pages "il y a 3 jours" or "vor 2 Wochen". Dates are
+// resolved relative to the scrape time.
+
+var (
+	// labels in front of the date itself
+	datePrefix = regexp.MustCompile(`^(?:(?:re)?posted|active|apply\s+(?:by|before)|deadline|last\s+date(?:\s+to\s+apply)?|closes|closing|ends|publié|publiée|veröffentlicht|publicado)(?:\s+(?:on|le|am|el))?\s*:?\s*`)
+
+	compactRelative = regexp.MustCompile(`^(\d+)\+?([a-zäéíñ]*)$`)
+	numericDate     = regexp.MustCompile(`^(\d{1,4})[./-](\d{1,2})[./-](\d{2,4})$`)
+	dayPattern      = regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th|er|º|\.)?$`)
+	yearPattern     = regexp.MustCompile(`^'?(\d{2}|\d{4})$`)
+)
+
+// Moments named by a word rather than a count, as days before now
+var namedDays = map[string]int{
+	"just now": 0, "now": 0, "moments ago": 0, "few seconds ago": 0, "today": 0,
+	"à l'instant": 0, "aujourd'hui": 0, "gerade eben": 0, "heute": 0, "ahora": 0, "hoy": 0,
+	"yesterday": 1, "hier": 1, "gestern": 1, "ayer": 1,
+}
+
+// Words that stand for a count in relative dates, as in "an hour ago" or
+// "il y a quelques jours"
+var countWords = map[string]int{
+	"a": 1, "an": 1, "one": 1, "un": 1, "une": 1, "ein": 1, "eine": 1, "einem": 1, "einer": 1, "einen": 1, "una": 1, "uno": 1,
+	"few": 3, "quelques": 3, "einige": 3, "einigen": 3, "unos": 3, "unas": 3,
+}
+
+// Units of relative dates in English, French, German and Spanish
+var dateUnits = map[string]time.Duration{}
+
+func init() {
+	for duration, names := range map[time.Duration][]string{
+		time.Second:        {"s", "sec", "secs", "second", "seconds", "seconde", "secondes", "sekunde", "sekunden", "segundo", "segundos"},
+		time.Minute:        {"m", "min", "mins", "minute", "minutes", "minuten", "minuto", "minutos"},
+		time.Hour:          {"h", "hr", "hrs", "hour", "hours", "heure", "heures", "stunde", "stunden", "hora", "horas"},
+		24 * time.Hour:     {"d", "day", "days", "jour", "jours", "tag", "tage", "tagen", "día", "días", "dia", "dias"},
+		7 * 24 * time.Hour: {"w", "wk", "wks", "week", "weeks", "semaine", "semaines", "woche", "wochen", "semana", "semanas"},
+		monthUnit:          {"mo", "mos", "month", "months", "mois", "monat", "monate", "monaten", "mes", "meses"},
+		yearUnit:           {"y", "yr", "yrs", "year", "years", "an", "ans", "année", "années", "jahr", "jahre", "jahren", "año", "años"},
+	} {
+		for _, name := range names {
+			dateUnits[name] = duration
+		}
+	}
+}
+
+// Stand-ins for the calendar units, which have no fixed duration
+const (
+	monthUnit time.Duration = -1
+	yearUnit  time.Duration = -2
+)
+
+var monthNames = map[string]time.Month{}
+
+func init() {
+	for month, names := range map[time.Month][]string{
+		time.January:   {"jan", "january", "janv", "janvier", "januar", "jän", "jänner", "ene", "enero"},
+		time.February:  {"feb", "february", "févr", "fevr", "février", "fevrier", "februar", "febrero"},
+		time.March:     {"mar", "march", "mars", "märz", "maerz", "marzo"},
+		time.April:     {"apr", "april", "avr", "avril", "abr", "abril"},
+		time.May:       {"may", "mai", "mayo"},
+		time.June:      {"jun", "june", "juin", "juni", "junio"},
+		time.July:      {"jul", "july", "juil", "juillet", "juli", "julio"},
+		time.August:    {"aug", "august", "août", "aout", "ago", "agosto"},
+		time.September: {"sep", "sept", "september", "septembre", "septiembre", "setiembre"},
+		time.October:   {"oct", "october", "octobre", "okt", "oktober", "octubre"},
+		time.November:  {"nov", "november", "novembre", "noviembre"},
+		time.December:  {"dec", "december", "déc", "décembre", "decembre", "dez", "dezember", "dic", "diciembre"},
+	} {
+		for _, name := range names {
+			monthNames[name] = month
+		}
+	}
+}
+
+// How far a date without a year may fall on the wrong side of now before
+// it is moved to the neighbouring year
+const (
+	postedSlack   = 2 * 24 * time.Hour
+	deadlineSlack = 60 * 24 * time.Hour
+)
+
+// ResolvePosted turns when a listing says it was posted into a time, e.g.
+// "3 days ago", "30+ days ago", "Just now", "Yesterday" or "Posted on 28
+// Dec". Dates without a year are taken to be in the past, so "28 Dec" read
+// in January is last December.
+func ResolvePosted(text string, now time.Time) (time.Time, bool) {
+	text = cleanDate(text)
+	if text == "" {
+		return time.Time{}, false
+	}
+	if days, ok := namedDays[text]; ok {
+		return now.AddDate(0, 0, -days), true
+	}
+	if t, ok := relativeDate(text, now); ok {
+		return t, true
+	}
+	t, hasYear, ok := absoluteDate(text, now)
+	if !ok {
+		return time.Time{}, false
+	}
+	if !hasYear && t.After(now.Add(postedSlack)) {
+		t = t.AddDate(-1, 0, 0)
+	}
+	return t, true
+}
+
+// ResolveDeadline turns an application deadline such as "Apply by 12 Aug",
+// "Aug 12", "12/08/2024" or "2024-08-12" into the end of that day. A date
+// without a year is put in the nearest year that does not leave it more
+// than two months past: "5 Jan" read in December is next January, and
+// "28 Dec" read in early January is the December that just ended.
+func ResolveDeadline(text string, now time.Time) (time.Time, bool) {
+	text = cleanDate(text)
+	if text == "" {
+		return time.Time{}, false
+	}
+	if days, ok := namedDays[text]; ok && days == 0 {
+		return endOfDay(now), true
+	}
+	t, hasYear, ok := absoluteDate(text, now)
+	if !ok {
+		return time.Time{}, false
+	}
+	if !hasYear {
+		t = t.AddDate(-1, 0, 0)
+		for endOfDay(t).Before(now.Add(-deadlineSlack)) {
+			t = t.AddDate(1, 0, 0)
+		}
+	}
+	return endOfDay(t), true
+}
+
+func cleanDate(text string) string {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	text = strings.ReplaceAll(text, "’", "'")
+	return strings.TrimSpace(datePrefix.ReplaceAllString(text, ""))
+}
+
+// relativeDate reads "3 days ago", "30+ days ago", "2w", "an hour ago",
+// "il y a un an", "vor 2 Wochen" or "hace 5 días": a count followed by a
+// unit, either as two words or run together.
+func relativeDate(text string, now time.Time) (time.Time, bool) {
+	words := strings.Fields(text)
+	for i, word := range words {
+		n, unitWord := 0, ""
+		if m := compactRelative.FindStringSubmatch(word); m != nil {
+			n, _ = strconv.Atoi(m[1])
+			unitWord = m[2]
+		} else if count, ok := countWords[word]; ok {
+			n = count
+		} else {
+			continue
+		}
+		if unitWord == "" && i+1 < len(words) {
+			unitWord = strings.TrimRight(words[i+1], ".,")
+		}
+		unit, ok := dateUnits[unitWord]
+		if !ok {
+			continue
+		}
+		switch unit {
+		case monthUnit:
+			return now.AddDate(0, -n, 0), true
+		case yearUnit:
+			return now.AddDate(-n, 0, 0), true
+		}
+		return now.Add(-time.Duration(n) * unit), true
+	}
+	return time.Time{}, false
+}
+
+// absoluteDate reads ISO dates, day first numeric dates and dates with a
+// month name in either order. It reports whether the text gave the year;
+// without one the date is put in now's year.
+func absoluteDate(text string, now time.Time) (t time.Time, hasYear, ok bool) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, text, now.Location()); err == nil {
+			return t, true, true
+		}
+	}
+
+	if m := numericDate.FindStringSubmatch(text); m != nil {
+		a, _ := strconv.Atoi(m[1])
+		b, _ := strconv.Atoi(m[2])
+		c, _ := strconv.Atoi(m[3])
+		var year, month, day int
+		switch {
+		case len(m[1]) == 4: // 2024/08/12
+			year, month, day = a, b, c
+		case b > 12: // 08/12/2024 can only be month first
+			day, month, year = b, a, c
+		default: // 12/08/2024, as India and Europe write it
+			day, month, year = a, b, c
+		}
+		return makeDate(fullYear(year), time.Month(month), day, now)
+	}
+
+	// "12 Aug", "12th August 2024", "Aug 12, 2024", "12-Aug-24", "12. März"
+	var month time.Month
+	day, year := 0, 0
+	hasYear = false
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '-' || r == '/'
+	}) {
+		word = strings.TrimSuffix(word, ".")
+		if word == "de" || word == "of" {
+			continue
+		}
+		if mon, ok := monthNames[word]; ok && month == 0 {
+			month = mon
+			continue
+		}
+		if m := dayPattern.FindStringSubmatch(word); m != nil && day == 0 {
+			day, _ = strconv.Atoi(m[1])
+			continue
+		}
+		if m := yearPattern.FindStringSubmatch(word); m != nil && !hasYear {
+			year, _ = strconv.Atoi(m[1])
+			year, hasYear = fullYear(year), true
+			continue
+		}
+		return time.Time{}, false, false
+	}
+	if month == 0 || day == 0 {
+		return time.Time{}, false, false
+	}
+	if !hasYear {
+		year = now.Year()
+	}
+	t, _, ok = makeDate(year, month, day, now)
+	return t, hasYear, ok
+}
+
+// makeDate builds a date, refusing days the month does not have.
+func makeDate(year int, month time.Month, day int, now time.Time) (time.Time, bool, bool) {
+	if month < time.January || month > time.December || day < 1 || day > 31 {
+		return time.Time{}, false, false
+	}
+	t := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+	if t.Day() != day {
+		return time.Time{}, false, false
+	}
+	return t, true, true
+}
+
+// fullYear expands a two digit year such as the "24" of "12 Aug '24".
+func fullYear(year int) int {
+	if year < 100 {
+		return 2000 + year
+	}
+	return year
+}
+
+func endOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 23, 59, 59, 0, t.Location())
+}
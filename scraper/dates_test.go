@@ -0,0 +1,96 @@
+package scraper
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
+
+func TestResolvePosted(t *testing.T) {
+	now := date(2026, time.January, 3, 10, 0)
+	tests := []struct {
+		text string
+		want time.Time // zero when the text is not a date
+	}{
+		{"3 days ago", date(2025, time.December, 31, 10, 0)},
+		{"30+ days ago", date(2025, time.December, 4, 10, 0)},
+		{"Posted 2 weeks ago", date(2025, time.December, 20, 10, 0)},
+		{"Reposted 1 week ago", date(2025, time.December, 27, 10, 0)},
+		{"an hour ago", date(2026, time.January, 3, 9, 0)},
+		{"Few hours ago", date(2026, time.January, 3, 7, 0)},
+		{"2w", date(2025, time.December, 20, 10, 0)},
+		{"1mo", date(2025, time.December, 3, 10, 0)},
+		{"Just Now", now},
+		{"Today", now},
+		{"Yesterday", date(2026, time.January, 2, 10, 0)},
+
+		// localized LinkedIn pages
+		{"il y a 3 jours", date(2025, time.December, 31, 10, 0)},
+		{"il y a un an", date(2025, time.January, 3, 10, 0)},
+		{"vor 2 Wochen", date(2025, time.December, 20, 10, 0)},
+		{"vor einem Tag", date(2026, time.January, 2, 10, 0)},
+		{"hace 5 días", date(2025, time.December, 29, 10, 0)},
+
+		// a date without a year is in the past, across the new year too
+		{"Posted on 28 Dec", date(2025, time.December, 28, 0, 0)},
+		{"2 Jan", date(2026, time.January, 2, 0, 0)},
+		{"12/08/2024", date(2024, time.August, 12, 0, 0)},
+		{"2024-08-12", date(2024, time.August, 12, 0, 0)},
+
+		{"", time.Time{}},
+		{"Be an early applicant", time.Time{}},
+	}
+	for _, tt := range tests {
+		got, ok := ResolvePosted(tt.text, now)
+		if ok != !tt.want.IsZero() || !got.Equal(tt.want) {
+			t.Errorf("ResolvePosted(%q) = %v, %v; want %v", tt.text, got, ok, tt.want)
+		}
+	}
+}
+
+func TestResolveDeadline(t *testing.T) {
+	endOf := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 23, 59, 59, 0, time.UTC)
+	}
+	tests := []struct {
+		now  time.Time
+		text string
+		want time.Time
+	}{
+		// early January: a December deadline is the one just past
+		{date(2026, time.January, 3, 10, 0), "Apply by 28 Dec", endOf(2025, time.December, 28)},
+		{date(2026, time.January, 3, 10, 0), "Apply by 15 Jan", endOf(2026, time.January, 15)},
+		{date(2026, time.January, 3, 10, 0), "Nov 20", endOf(2025, time.November, 20)},
+		{date(2026, time.January, 3, 10, 0), "Oct 20", endOf(2026, time.October, 20)}, // past beyond deadlineSlack
+		{date(2026, time.January, 3, 10, 0), "Apply by 12 Aug", endOf(2026, time.August, 12)},
+
+		// late December: a January deadline is the one to come
+		{date(2025, time.December, 20, 10, 0), "5 Jan", endOf(2026, time.January, 5)},
+		{date(2025, time.December, 20, 10, 0), "Apply by 31 Dec", endOf(2025, time.December, 31)},
+		{date(2025, time.December, 20, 10, 0), "Deadline: 1 Dec", endOf(2025, time.December, 1)},
+		{date(2025, time.December, 20, 10, 0), "12 Aug", endOf(2026, time.August, 12)},
+
+		// the year given wins
+		{date(2026, time.January, 3, 10, 0), "Aug 12, 2025", endOf(2025, time.August, 12)},
+		{date(2026, time.January, 3, 10, 0), "12th August 2024", endOf(2024, time.August, 12)},
+		{date(2026, time.January, 3, 10, 0), "12-Aug-24", endOf(2024, time.August, 12)},
+		{date(2026, time.January, 3, 10, 0), "12/08/2026", endOf(2026, time.August, 12)},
+		{date(2026, time.January, 3, 10, 0), "2026-02-15", endOf(2026, time.February, 15)},
+
+		// other languages
+		{date(2026, time.January, 3, 10, 0), "12. März", endOf(2026, time.March, 12)},
+		{date(2026, time.January, 3, 10, 0), "12 de agosto de 2026", endOf(2026, time.August, 12)},
+
+		{date(2026, time.January, 3, 10, 0), "31 Feb", time.Time{}},
+		{date(2026, time.January, 3, 10, 0), "soon", time.Time{}},
+	}
+	for _, tt := range tests {
+		got, ok := ResolveDeadline(tt.text, tt.now)
+		if ok != !tt.want.IsZero() || !got.Equal(tt.want) {
+			t.Errorf("ResolveDeadline(%q) on %s = %v, %v; want %v", tt.text, tt.now.Format(time.DateOnly), got, ok, tt.want)
+		}
+	}
+}
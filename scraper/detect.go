@@ -0,0 +1,176 @@
+package scraper
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Fingerprint lists what gives a platform's pages away.
+type Fingerprint struct {
+	// Hosts of the site, matched against the canonical and og:url links
+	Hosts []string
+	// SiteNames matched against og:site_name and application-name
+	SiteNames []string
+	// Markers are fields of the platform's selectors that only its pages
+	// match, such as "card"
+	Markers []string
+	// Strings found in the platform's pages or API responses, such as API
+	// field names, state blob keys or its own domain in links
+	Strings []string
+}
+
+// Fingerprinted is implemented by scrapers whose pages can be recognized.
+type Fingerprinted interface {
+	Scraper
+	Fingerprint() Fingerprint
+}
+
+// Detection is the outcome of Detect for one platform.
+type Detection struct {
+	Platform string   `json:"platform"`
+	Score    int      `json:"score"`
+	Signals  []string `json:"signals"`
+}
+
+// Weights of the signals Detect looks for
+const (
+	hostScore     = 5
+	siteNameScore = 3
+	markerScore   = 4
+	listScore     = 2 // a marker matching several elements, as on a results page
+	stringScore   = 2
+)
+
+// Detect works out which platform a saved page or API response comes from.
+// It returns the best candidate, or an error naming the tied candidates
+// when the document is ambiguous.
+func Detect(data []byte) (Detection, error) {
+	candidates := DetectAll(data)
+	if len(candidates) == 0 {
+		return Detection{}, fmt.Errorf("could not tell which platform this document comes from")
+	}
+	if len(candidates) > 1 && candidates[1].Score == candidates[0].Score {
+		return Detection{}, fmt.Errorf("document matches both %s and %s", candidates[0].Platform, candidates[1].Platform)
+	}
+	return candidates[0], nil
+}
+
+// DetectAll scores data against every fingerprinted platform and returns
+// those with any signal, best first.
+func DetectAll(data []byte) []Detection {
+	trimmed := bytes.TrimSpace(data)
+	isJSON := len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+
+	var doc *goquery.Document
+	var hosts, siteNames []string
+	if !isJSON {
+		var err error
+		doc, err = goquery.NewDocumentFromReader(bytes.NewReader(data))
+		if err == nil {
+			hosts = pageHosts(doc)
+			siteNames = pageSiteNames(doc)
+		}
+	}
+
+	var candidates []Detection
+	for _, name := range Platforms() {
+		s, _ := Get(name)
+		fs, ok := s.(Fingerprinted)
+		if !ok {
+			continue
+		}
+		fp := fs.Fingerprint()
+		d := Detection{Platform: name}
+
+		for _, host := range hosts {
+			if matchHost(host, fp.Hosts) {
+				d.Score += hostScore
+				d.Signals = append(d.Signals, "host "+host)
+				break
+			}
+		}
+		for _, site := range siteNames {
+			if matchName(site, fp.SiteNames) {
+				d.Score += siteNameScore
+				d.Signals = append(d.Signals, "site name "+site)
+				break
+			}
+		}
+		if doc != nil {
+			sel := SelectorsFor(name)
+			for _, field := range fp.Markers {
+				if n := sel.Find(doc.Selection, field).Length(); n > 0 {
+					d.Score += markerScore
+					if n > 2 {
+						d.Score += listScore
+					}
+					d.Signals = append(d.Signals, fmt.Sprintf("%d %s", n, field))
+				}
+			}
+		}
+		for _, str := range fp.Strings {
+			if bytes.Contains(data, []byte(str)) {
+				d.Score += stringScore
+				d.Signals = append(d.Signals, "contains "+str)
+			}
+		}
+
+		if d.Score > 0 {
+			candidates = append(candidates, d)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return candidates
+}
+
+// pageHosts returns the hosts a saved page names itself by.
+func pageHosts(doc *goquery.Document) []string {
+	var hosts []string
+	doc.Find("link[rel='canonical'], meta[property='og:url'], base[href], link[rel='alternate'][hreflang]").Each(func(_ int, s *goquery.Selection) {
+		ref, ok := s.Attr("href")
+		if !ok {
+			ref, _ = s.Attr("content")
+		}
+		if u, err := url.Parse(strings.TrimSpace(ref)); err == nil && u.Host != "" {
+			hosts = append(hosts, strings.ToLower(u.Host))
+		}
+	})
+	return hosts
+}
+
+func pageSiteNames(doc *goquery.Document) []string {
+	var names []string
+	doc.Find("meta[property='og:site_name'], meta[name='application-name'], meta[name='apple-mobile-web-app-title']").Each(func(_ int, s *goquery.Selection) {
+		if name := strings.TrimSpace(s.AttrOr("content", "")); name != "" {
+			names = append(names, name)
+		}
+	})
+	return names
+}
+
+// matchHost reports whether host is one of hosts or a subdomain of one.
+func matchHost(host string, hosts []string) bool {
+	for _, h := range hosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchName(name string, names []string) bool {
+	for _, n := range names {
+		if strings.EqualFold(name, n) {
+			return true
+		}
+	}
+	return false
+}
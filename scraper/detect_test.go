@@ -0,0 +1,68 @@
+package scraper_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	_ "aiapply/cuvette"
+	_ "aiapply/greenhouse"
+	_ "aiapply/indeed"
+	_ "aiapply/internshala"
+	_ "aiapply/lever"
+	_ "aiapply/linkedin"
+	_ "aiapply/naukri"
+	_ "aiapply/schemaorg"
+	"aiapply/scraper"
+	_ "aiapply/wellfound"
+)
+
+func TestDetectFixtures(t *testing.T) {
+	tests := []struct {
+		file     string
+		platform string
+	}{
+		{"../naukri/naukri.html", "naukri"},
+		{"../internshala/internshala.html", "internshala"},
+		{"../indeed/indeed.html", "indeed"},
+		{"../lever/lever.json", "lever"},
+		{"../greenhouse/greenhouse.json", "greenhouse"},
+		{"../wellfound/wellfound.html", "wellfound"},
+		{"../wellfound/wellfound_state.html", "wellfound"},
+		{"../schemaorg/careers.html", "schemaorg"},
+		{"../xyz.json", "cuvette"},
+		// a search page with a job open goes to the search scraper, a job
+		// page of its own to the detail scraper
+		{"../linkedin/network.html", "linkedin"},
+		{"../linkedin/job_view.html", "linkedin-job"},
+	}
+	for _, tt := range tests {
+		data, err := os.ReadFile(tt.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := scraper.Detect(data)
+		if err != nil || got.Platform != tt.platform {
+			t.Errorf("Detect(%s) = %s, %v; want %s (candidates %v)", tt.file, got.Platform, err, tt.platform, scraper.DetectAll(data))
+		}
+	}
+}
+
+func TestDetectAmbiguous(t *testing.T) {
+	tests := []struct {
+		name string
+		page string
+		err  string
+	}{
+		// nothing but the host, which both LinkedIn scrapers claim
+		{"tie", `<html><head><link rel="canonical" href="https://www.linkedin.com/jobs/"></head><body></body></html>`, "both linkedin and linkedin-job"},
+		{"unknown", `<html><head><title>Careers</title></head><body><p>Join us</p></body></html>`, "could not tell"},
+		{"empty", ``, "could not tell"},
+	}
+	for _, tt := range tests {
+		got, err := scraper.Detect([]byte(tt.page))
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: Detect = %+v, %v; want an error containing %q", tt.name, got, err, tt.err)
+		}
+	}
+}
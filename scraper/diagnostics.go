@@ -0,0 +1,107 @@
+package scraper
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// CardCounter is implemented by scrapers that can tell how many listings a
+// page holds, including the ones they could not turn into jobs.
+type CardCounter interface {
+	CountCards(r io.Reader) (int, error)
+}
+
+// Diagnostics describe how well a page was parsed.
+type Diagnostics struct {
+	Platform        string             `json:"platform"`
+	SelectorVersion int                `json:"selector_version"`
+	Cards           int                `json:"cards"` // listings matched on the page
+	Jobs            int                `json:"jobs"`  // listings turned into jobs
+	FillRates       map[string]float64 `json:"fill_rates"`
+	Warnings        []string           `json:"warnings"`
+}
+
+// Fields every listing should have; a page where most jobs lack one is a
+// sign the platform changed its markup.
+var requiredFields = []string{"role", "company", "location"}
+
+const (
+	// Warn when a required field is empty for more than this share of jobs
+	requiredFillRate = 0.5
+	// Warn when a field filled this much less often than in the last run
+	fillRateDrop = 0.3
+)
+
+// Diagnose measures how often each field of jobs was filled. cards is the
+// number of listings the page matched, or -1 when the scraper cannot tell.
+func Diagnose(platform string, cards int, jobs []Job) Diagnostics {
+	_, source := CurrentSelectors()
+	d := Diagnostics{
+		Platform:        platform,
+		SelectorVersion: source.Version,
+		Cards:           cards,
+		Jobs:            len(jobs),
+		FillRates:       make(map[string]float64),
+	}
+	if cards < 0 {
+		d.Cards = len(jobs)
+	}
+	if len(jobs) == 0 {
+		return d
+	}
+
+	// core fields are reported even when no job has them
+	filled := map[string]int{"role": 0, "company": 0, "location": 0, "salary": 0, "url": 0, "skills": 0, "posted_at": 0}
+	count := func(field string, ok bool) {
+		if ok {
+			filled[field]++
+		}
+	}
+	for _, job := range jobs {
+		count("role", job.Role != "")
+		count("company", job.Company != "")
+		count("location", job.Location != "")
+		count("salary", job.Salary != "")
+		count("url", job.URL != "")
+		count("skills", len(job.Skills) > 0)
+		count("posted_at", job.PostedAt != nil)
+		for key, value := range job.Extras {
+			count(key, value != "")
+		}
+	}
+	for field, n := range filled {
+		d.FillRates[field] = math.Round(float64(n)/float64(len(jobs))*100) / 100
+	}
+
+	if d.Cards > d.Jobs {
+		d.Warnings = append(d.Warnings, fmt.Sprintf("%d of %d listings could not be read", d.Cards-d.Jobs, d.Cards))
+	}
+	for _, field := range requiredFields {
+		if rate := d.FillRates[field]; rate < requiredFillRate {
+			d.Warnings = append(d.Warnings, fmt.Sprintf("%s is empty for %.0f%% of jobs", field, (1-rate)*100))
+		}
+	}
+	return d
+}
+
+// CompareWith warns about fields filled much less often than in an earlier
+// run of the same platform.
+func (d *Diagnostics) CompareWith(previous map[string]float64) {
+	if d.Jobs == 0 {
+		return
+	}
+	fields := make([]string, 0, len(previous))
+	for field := range previous {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		before, now := previous[field], d.FillRates[field]
+		if before-now >= fillRateDrop {
+			d.Warnings = append(d.Warnings, fmt.Sprintf("%s filled for %.0f%% of jobs, down from %.0f%% in the previous run", field, now*100, before*100))
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// FetchHints tell the Fetcher how to page through a platform's results.
+type FetchHints struct {
+	// Card matches one listing on a results page
+	Card string
+	// Next matches the control that opens the next page of results; empty
+	// for platforms that load more listings as the page is scrolled
+	Next string
+}
+
+// Fetchable is implemented by scrapers whose pages can be loaded live
+// instead of being saved and uploaded by hand.
+type Fetchable interface {
+	Scraper
+	FetchHints() FetchHints
+}
+
+// Fetcher defaults
+const (
+	DefaultFetchLimit    = 25
+	DefaultFetchTimeout  = 2 * time.Minute
+	DefaultFetchMaxPages = 10
+	DefaultFetchBrowsers = 2
+	defaultScrollDelay   = 1500 * time.Millisecond
+	staleScrolls         = 3
+)
+
+// ErrFetcherBusy is returned by Fetch while every browser the Fetcher may
+// run is in use.
+var ErrFetcherBusy = errors.New("too many pages are being fetched, try again later")
+
+// Fetcher drives a headless Chrome to a search URL, scrolls and follows
+// pagination until enough listings are on screen, and hands the rendered
+// DOM to the platform's Scraper. Only URLs on the platform's own hosts, as
+// its Fingerprint lists them, are loaded; AllowAnyHost lifts that so a local
+// HTTP server with saved pages can stand in for the real sites.
+type Fetcher struct {
+	ExecPath     string        // Chrome binary; found on PATH when empty
+	Headless     bool          // run without a window
+	Timeout      time.Duration // for the whole fetch
+	MaxPages     int           // pagination stops after this many pages
+	ScrollDelay  time.Duration // wait after each scroll or page change
+	AllowAnyHost bool          // load URLs outside the platform's hosts
+
+	// browsers holds a slot per Chrome running; nil places no limit
+	browsers chan struct{}
+}
+
+// NewFetcher returns a headless Fetcher. CHROME_PATH overrides the browser
+// binary, FETCH_TIMEOUT (e.g. "90s") the time allowed per fetch and
+// FETCH_BROWSERS how many fetches may run at once.
+func NewFetcher() *Fetcher {
+	f := &Fetcher{
+		ExecPath:    os.Getenv("CHROME_PATH"),
+		Headless:    true,
+		Timeout:     DefaultFetchTimeout,
+		MaxPages:    DefaultFetchMaxPages,
+		ScrollDelay: defaultScrollDelay,
+	}
+	if v := os.Getenv("FETCH_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			f.Timeout = d
+		}
+	}
+	browsers := DefaultFetchBrowsers
+	if n, err := strconv.Atoi(os.Getenv("FETCH_BROWSERS")); err == nil && n > 0 {
+		browsers = n
+	}
+	f.browsers = make(chan struct{}, browsers)
+	return f
+}
+
+// Fetch loads pageURL in the browser and returns up to limit jobs parsed by
+// the scraper registered for platform. Jobs seen on several pages are
+// returned once.
+func (f *Fetcher) Fetch(ctx context.Context, platform, pageURL string, limit int) ([]Job, error) {
+	s, ok := Get(platform)
+	if !ok {
+		return nil, fmt.Errorf("platform %q not supported", platform)
+	}
+	fs, ok := s.(Fetchable)
+	if !ok {
+		return nil, fmt.Errorf("platform %q cannot be fetched", platform)
+	}
+	if err := f.CheckURL(s, pageURL); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultFetchLimit
+	}
+	hints := fs.FetchHints()
+
+	// each Chrome takes hundreds of megabytes, so callers over the limit are
+	// turned away rather than queued
+	if f.browsers != nil {
+		select {
+		case f.browsers <- struct{}{}:
+			defer func() { <-f.browsers }()
+		default:
+			return nil, ErrFetcherBusy
+		}
+	}
+
+	opts := append(chromedp.DefaultExecAllocatorOptions[:], chromedp.Flag("headless", f.Headless))
+	if f.ExecPath != "" {
+		opts = append(opts, chromedp.ExecPath(f.ExecPath))
+	}
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(ctx, opts...)
+	defer cancelAlloc()
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+	defer cancelBrowser()
+	if f.Timeout > 0 {
+		var cancel context.CancelFunc
+		browserCtx, cancel = context.WithTimeout(browserCtx, f.Timeout)
+		defer cancel()
+	}
+
+	if err := chromedp.Run(browserCtx, chromedp.Navigate(pageURL), chromedp.WaitReady("body")); err != nil {
+		return nil, fmt.Errorf("could not load %s: %w", pageURL, err)
+	}
+
+	var jobs []Job
+	seen := make(map[string]bool)
+	maxPages := f.MaxPages
+	if maxPages <= 0 {
+		maxPages = DefaultFetchMaxPages
+	}
+	for page := 1; ; page++ {
+		if err := f.scroll(browserCtx, hints.Card, limit-len(jobs)); err != nil {
+			return jobs, err
+		}
+
+		var rendered string
+		if err := chromedp.Run(browserCtx, chromedp.OuterHTML("html", &rendered, chromedp.ByQuery)); err != nil {
+			return jobs, fmt.Errorf("could not read page %d: %w", page, err)
+		}
+		found, err := s.Scrape(strings.NewReader(rendered))
+		if err != nil {
+			return jobs, fmt.Errorf("could not parse page %d: %w", page, err)
+		}
+		for _, job := range found {
+			key := job.ID
+			if key == "" {
+				key = job.URL
+			}
+			if key != "" && seen[key] {
+				continue
+			}
+			seen[key] = true
+			jobs = append(jobs, job)
+		}
+
+		if len(jobs) >= limit || hints.Next == "" || page >= maxPages {
+			break
+		}
+		moved, err := f.nextPage(browserCtx, hints.Next)
+		if err != nil {
+			return jobs, err
+		}
+		if !moved {
+			break
+		}
+	}
+
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+// CheckURL reports whether the Fetcher would load pageURL for s: an http or
+// https URL on one of the hosts of the platform's Fingerprint.
+func (f *Fetcher) CheckURL(s Scraper, pageURL string) error {
+	u, err := url.Parse(pageURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid URL %q", pageURL)
+	}
+	if f.AllowAnyHost {
+		return nil
+	}
+	var hosts []string
+	if fp, ok := s.(Fingerprinted); ok {
+		hosts = fp.Fingerprint().Hosts
+	}
+	if !matchHost(strings.ToLower(u.Hostname()), hosts) {
+		return fmt.Errorf("URL %q is not on a %s host", pageURL, s.Platform())
+	}
+	return nil
+}
+
+// scroll brings the last listing into view until want listings are loaded
+// or a few scrolls in a row load nothing new.
+func (f *Fetcher) scroll(ctx context.Context, card string, want int) error {
+	script := fmt.Sprintf(`(() => {
+		const cards = document.querySelectorAll(%q);
+		if (cards.length > 0) {
+			cards[cards.length - 1].scrollIntoView();
+		} else {
+			window.scrollTo(0, document.body.scrollHeight);
+		}
+		return cards.length;
+	})()`, card)
+
+	last, stale := -1, 0
+	for stale < staleScrolls {
+		var count int
+		if err := chromedp.Run(ctx, chromedp.Evaluate(script, &count), chromedp.Sleep(f.ScrollDelay)); err != nil {
+			return fmt.Errorf("could not scroll page: %w", err)
+		}
+		if count >= want {
+			return nil
+		}
+		if count == last {
+			stale++
+		} else {
+			last, stale = count, 0
+		}
+	}
+	return nil
+}
+
+// nextPage clicks the next-page control and reports whether there was one
+// to click.
+func (f *Fetcher) nextPage(ctx context.Context, next string) (bool, error) {
+	script := fmt.Sprintf(`(() => {
+		const el = document.querySelector(%q);
+		if (!el || el.disabled || el.getAttribute("aria-disabled") === "true") {
+			return false;
+		}
+		el.click();
+		return true;
+	})()`, next)
+
+	var clicked bool
+	if err := chromedp.Run(ctx, chromedp.Evaluate(script, &clicked)); err != nil {
+		return false, fmt.Errorf("could not open next page: %w", err)
+	}
+	if !clicked {
+		return false, nil
+	}
+	if err := chromedp.Run(ctx, chromedp.Sleep(f.ScrollDelay), chromedp.WaitReady("body")); err != nil {
+		return false, fmt.Errorf("could not load next page: %w", err)
+	}
+	return true, nil
+}
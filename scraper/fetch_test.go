@@ -0,0 +1,152 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// fixtureScraper reads the pages served by fixtureServer.
+type fixtureScraper struct{}
+
+func (fixtureScraper) Platform() string { return "fixture" }
+
+func (fixtureScraper) Fingerprint() Fingerprint {
+	return Fingerprint{Hosts: []string{"jobs.example.com"}}
+}
+
+func (fixtureScraper) FetchHints() FetchHints {
+	return FetchHints{Card: ".job", Next: "a.next"}
+}
+
+func (fixtureScraper) Scrape(r io.Reader) ([]Job, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+	var jobs []Job
+	doc.Find(".job").Each(func(_ int, card *goquery.Selection) {
+		id, _ := card.Attr("data-id")
+		jobs = append(jobs, Job{ID: id, Platform: "fixture", Role: strings.TrimSpace(card.Text())})
+	})
+	return jobs, nil
+}
+
+func init() {
+	Register(fixtureScraper{})
+}
+
+// fixtureServer serves two pages of three listings each; the first links
+// to the second and repeats one of its listings.
+func fixtureServer() *httptest.Server {
+	page := func(w http.ResponseWriter, ids []int, next string) {
+		fmt.Fprint(w, "<html><body>")
+		for _, id := range ids {
+			fmt.Fprintf(w, `<div class="job" data-id="%d">Engineer %d</div>`, id, id)
+		}
+		if next != "" {
+			fmt.Fprintf(w, `<a class="next" href="%s">Next</a>`, next)
+		}
+		fmt.Fprint(w, "</body></html>")
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		page(w, []int{1, 2, 3}, "/search/2")
+	})
+	mux.HandleFunc("/search/2", func(w http.ResponseWriter, r *http.Request) {
+		page(w, []int{3, 4, 5, 6}, "")
+	})
+	return httptest.NewServer(mux)
+}
+
+func chromePath(t *testing.T) string {
+	if path := os.Getenv("CHROME_PATH"); path != "" {
+		return path
+	}
+	for _, name := range []string{"google-chrome", "google-chrome-stable", "chromium", "chromium-browser", "headless-shell", "chrome"} {
+		if path, err := exec.LookPath(name); err == nil {
+			return path
+		}
+	}
+	t.Skip("Chrome is not installed")
+	return ""
+}
+
+func TestFetcherFollowsPages(t *testing.T) {
+	chrome := chromePath(t)
+	server := fixtureServer()
+	defer server.Close()
+
+	f := NewFetcher()
+	f.ExecPath = chrome
+	f.ScrollDelay = 100 * time.Millisecond
+	f.Timeout = 30 * time.Second
+	f.AllowAnyHost = true
+
+	jobs, err := f.Fetch(context.Background(), "fixture", server.URL+"/search", 5)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	var ids []string
+	for _, job := range jobs {
+		ids = append(ids, job.ID)
+	}
+	if got, want := strings.Join(ids, ","), "1,2,3,4,5"; got != want {
+		t.Errorf("fetched jobs %s, want %s", got, want)
+	}
+}
+
+func TestFetcherCheckURL(t *testing.T) {
+	server := fixtureServer()
+	defer server.Close()
+
+	tests := []struct {
+		url   string
+		allow bool
+		ok    bool
+	}{
+		{"https://jobs.example.com/search", false, true},
+		{"https://www.jobs.example.com/search", false, true},
+		{"https://JOBS.EXAMPLE.COM:8443/search", false, true},
+		{"https://evil-jobs.example.com/search", false, false},
+		{"https://jobs.example.com.evil.test/search", false, false},
+		{"http://localhost/search", false, false},
+		{"http://169.254.169.254/latest/meta-data", false, false},
+		{server.URL + "/search", false, false},
+		{server.URL + "/search", true, true},
+		{"file:///etc/passwd", true, false},
+		{"jobs.example.com/search", false, false},
+	}
+	for _, tt := range tests {
+		f := &Fetcher{AllowAnyHost: tt.allow}
+		err := f.CheckURL(fixtureScraper{}, tt.url)
+		if (err == nil) != tt.ok {
+			t.Errorf("CheckURL(%q, AllowAnyHost %v) = %v, want ok %v", tt.url, tt.allow, err, tt.ok)
+		}
+	}
+}
+
+func TestFetcherTurnsAwayWhenBusy(t *testing.T) {
+	t.Setenv("FETCH_BROWSERS", "1")
+	f := NewFetcher()
+	f.AllowAnyHost = true
+	if cap(f.browsers) != 1 {
+		t.Fatalf("FETCH_BROWSERS=1 gave %d slots", cap(f.browsers))
+	}
+
+	// a fetch in progress holds the only browser
+	f.browsers <- struct{}{}
+	if _, err := f.Fetch(context.Background(), "fixture", "http://127.0.0.1/search", 5); !errors.Is(err, ErrFetcherBusy) {
+		t.Errorf("Fetch while busy = %v, want ErrFetcherBusy", err)
+	}
+}
@@ -0,0 +1,37 @@
+package scraper
+
+import "time"
+
+// Job is a listing scraped from any platform, in the one shape the rest of
+// the app works with. Platform specific fields go to Extras.
+type Job struct {
+	ID       string            `json:"id"`
+	Platform string            `json:"platform"`
+	Role     string            `json:"role"`
+	Company  string            `json:"company"`
+	Location string            `json:"location"`
+	Salary   string            `json:"salary"`
+	URL      string            `json:"url"`
+	Skills   []string          `json:"skills"`
+	PostedAt *time.Time        `json:"posted_at"`
+	Extras   map[string]string `json:"extras,omitempty"`
+
+	// Compensation is Salary parsed by Normalize
+	Compensation *Compensation `json:"compensation"`
+	// Workplace is Location parsed by Normalize
+	Workplace *Workplace `json:"workplace"`
+	// ApplyBy is the application deadline, read from the apply_by extra by
+	// Normalize when the platform does not give a time
+	ApplyBy *time.Time `json:"apply_by"`
+}
+
+// SetExtra stores a platform specific field, skipping empty values.
+func (j *Job) SetExtra(key, value string) {
+	if value == "" {
+		return
+	}
+	if j.Extras == nil {
+		j.Extras = make(map[string]string)
+	}
+	j.Extras[key] = value
+}
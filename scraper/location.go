@@ -0,0 +1,339 @@
+package scraper
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Remote policies of a Workplace
+const (
+	PolicyRemote = "remote"
+	PolicyHybrid = "hybrid"
+	PolicyOnsite = "onsite"
+)
+
+// Place is one location a job is offered in. Metro groups cities that make up
+// one job market, e.g. Gurugram and Noida are both "Delhi NCR".
+type Place struct {
+	City    string `json:"city,omitempty"`
+	Metro   string `json:"metro,omitempty"`
+	Region  string `json:"region,omitempty"`
+	Country string `json:"country,omitempty"`
+}
+
+// Workplace is a location caption broken into places and a remote policy.
+// City, Region and Country repeat the first place that names them.
+type Workplace struct {
+	Policy  string  `json:"policy"` // remote, hybrid, onsite, or empty when not stated
+	City    string  `json:"city"`
+	Region  string  `json:"region"`
+	Country string  `json:"country"`
+	Places  []Place `json:"places"`
+}
+
+type knownCity struct {
+	city, metro, region, country string
+}
+
+// cityAliases maps lower-case spellings to the canonical city.
+var cityAliases = map[string]knownCity{}
+
+func init() {
+	for _, c := range []struct {
+		knownCity
+		aliases []string
+	}{
+		{knownCity{"Bengaluru", "Bengaluru", "Karnataka", "India"}, []string{"bengaluru", "bangalore", "bangalore urban", "bangalore rural", "bengaluru urban", "bengaluru rural", "blr"}},
+		{knownCity{"Mysuru", "", "Karnataka", "India"}, []string{"mysuru", "mysore"}},
+		{knownCity{"Gurugram", "Delhi NCR", "Haryana", "India"}, []string{"gurugram", "gurgaon", "ggn"}},
+		{knownCity{"Noida", "Delhi NCR", "Uttar Pradesh", "India"}, []string{"noida", "greater noida"}},
+		{knownCity{"New Delhi", "Delhi NCR", "Delhi", "India"}, []string{"new delhi", "delhi"}},
+		{knownCity{"Faridabad", "Delhi NCR", "Haryana", "India"}, []string{"faridabad"}},
+		{knownCity{"Ghaziabad", "Delhi NCR", "Uttar Pradesh", "India"}, []string{"ghaziabad"}},
+		{knownCity{"", "Delhi NCR", "", "India"}, []string{"ncr", "delhi ncr", "delhi/ncr", "national capital region"}},
+		{knownCity{"Mumbai", "Mumbai", "Maharashtra", "India"}, []string{"mumbai", "bombay", "greater mumbai", "mumbai metropolitan region"}},
+		{knownCity{"Navi Mumbai", "Mumbai", "Maharashtra", "India"}, []string{"navi mumbai"}},
+		{knownCity{"Thane", "Mumbai", "Maharashtra", "India"}, []string{"thane"}},
+		{knownCity{"Pune", "Pune", "Maharashtra", "India"}, []string{"pune", "poona", "pimpri-chinchwad"}},
+		{knownCity{"Nagpur", "", "Maharashtra", "India"}, []string{"nagpur"}},
+		{knownCity{"Hyderabad", "Hyderabad", "Telangana", "India"}, []string{"hyderabad", "secunderabad", "cyberabad"}},
+		{knownCity{"Chennai", "Chennai", "Tamil Nadu", "India"}, []string{"chennai", "madras"}},
+		{knownCity{"Coimbatore", "", "Tamil Nadu", "India"}, []string{"coimbatore"}},
+		{knownCity{"Kolkata", "Kolkata", "West Bengal", "India"}, []string{"kolkata", "calcutta"}},
+		{knownCity{"Ahmedabad", "", "Gujarat", "India"}, []string{"ahmedabad", "amdavad"}},
+		{knownCity{"Gandhinagar", "", "Gujarat", "India"}, []string{"gandhinagar"}},
+		{knownCity{"Jaipur", "", "Rajasthan", "India"}, []string{"jaipur"}},
+		{knownCity{"Jodhpur", "", "Rajasthan", "India"}, []string{"jodhpur"}},
+		{knownCity{"Indore", "", "Madhya Pradesh", "India"}, []string{"indore"}},
+		{knownCity{"Bhopal", "", "Madhya Pradesh", "India"}, []string{"bhopal"}},
+		{knownCity{"Chandigarh", "Chandigarh Tricity", "Chandigarh", "India"}, []string{"chandigarh"}},
+		{knownCity{"Mohali", "Chandigarh Tricity", "Punjab", "India"}, []string{"mohali", "sas nagar"}},
+		{knownCity{"Panchkula", "Chandigarh Tricity", "Haryana", "India"}, []string{"panchkula"}},
+		{knownCity{"Kochi", "", "Kerala", "India"}, []string{"kochi", "cochin", "ernakulam"}},
+		{knownCity{"Thiruvananthapuram", "", "Kerala", "India"}, []string{"thiruvananthapuram", "trivandrum"}},
+		{knownCity{"Visakhapatnam", "", "Andhra Pradesh", "India"}, []string{"visakhapatnam", "vizag"}},
+		{knownCity{"Bhubaneswar", "", "Odisha", "India"}, []string{"bhubaneswar", "bhubaneshwar"}},
+		{knownCity{"Lucknow", "", "Uttar Pradesh", "India"}, []string{"lucknow"}},
+		{knownCity{"San Francisco", "San Francisco Bay Area", "California", "United States"}, []string{"san francisco", "sf"}},
+		{knownCity{"", "San Francisco Bay Area", "California", "United States"}, []string{"bay area", "san francisco bay area", "sf bay area"}},
+		{knownCity{"New York", "New York", "New York", "United States"}, []string{"new york", "new york city", "nyc"}},
+		{knownCity{"London", "London", "England", "United Kingdom"}, []string{"london", "greater london"}},
+		{knownCity{"Singapore", "", "", "Singapore"}, []string{"singapore"}},
+		{knownCity{"Dubai", "", "Dubai", "United Arab Emirates"}, []string{"dubai"}},
+		{knownCity{"Sydney", "", "New South Wales", "Australia"}, []string{"sydney"}},
+		{knownCity{"Toronto", "", "Ontario", "Canada"}, []string{"toronto"}},
+		{knownCity{"Berlin", "", "Berlin", "Germany"}, []string{"berlin"}},
+		{knownCity{"Jakarta", "", "", "Indonesia"}, []string{"jakarta"}},
+		{knownCity{"Johor Bahru", "", "Johor", "Malaysia"}, []string{"johor bahru"}},
+	} {
+		for _, alias := range c.aliases {
+			cityAliases[alias] = c.knownCity
+		}
+	}
+}
+
+// regions maps lower-case region names to their canonical name and country.
+var regions = map[string][2]string{
+	"karnataka": {"Karnataka", "India"}, "haryana": {"Haryana", "India"}, "maharashtra": {"Maharashtra", "India"},
+	"tamil nadu": {"Tamil Nadu", "India"}, "telangana": {"Telangana", "India"}, "andhra pradesh": {"Andhra Pradesh", "India"},
+	"uttar pradesh": {"Uttar Pradesh", "India"}, "delhi": {"Delhi", "India"}, "west bengal": {"West Bengal", "India"},
+	"kerala": {"Kerala", "India"}, "gujarat": {"Gujarat", "India"}, "rajasthan": {"Rajasthan", "India"},
+	"madhya pradesh": {"Madhya Pradesh", "India"}, "punjab": {"Punjab", "India"}, "goa": {"Goa", "India"},
+	"odisha": {"Odisha", "India"}, "bihar": {"Bihar", "India"}, "assam": {"Assam", "India"},
+	"chandigarh": {"Chandigarh", "India"}, "uttarakhand": {"Uttarakhand", "India"}, "jharkhand": {"Jharkhand", "India"},
+	"england": {"England", "United Kingdom"}, "scotland": {"Scotland", "United Kingdom"}, "wales": {"Wales", "United Kingdom"},
+	"new south wales": {"New South Wales", "Australia"}, "victoria": {"Victoria", "Australia"},
+	"ontario": {"Ontario", "Canada"}, "british columbia": {"British Columbia", "Canada"},
+	"california": {"California", "United States"}, "texas": {"Texas", "United States"}, "washington": {"Washington", "United States"},
+}
+
+// countries maps lower-case country names and codes to the canonical name.
+var countries = map[string]string{
+	"india":         "India",
+	"united states": "United States", "united states of america": "United States", "usa": "United States", "us": "United States",
+	"united kingdom": "United Kingdom", "uk": "United Kingdom", "great britain": "United Kingdom",
+	"canada": "Canada", "australia": "Australia", "germany": "Germany", "france": "France", "netherlands": "Netherlands",
+	"ireland": "Ireland", "singapore": "Singapore", "united arab emirates": "United Arab Emirates", "uae": "United Arab Emirates",
+	"philippines": "Philippines", "egypt": "Egypt", "indonesia": "Indonesia", "malaysia": "Malaysia", "japan": "Japan",
+	"brazil": "Brazil", "mexico": "Mexico", "spain": "Spain", "poland": "Poland", "portugal": "Portugal",
+	"israel": "Israel", "nigeria": "Nigeria", "kenya": "Kenya", "pakistan": "Pakistan", "bangladesh": "Bangladesh",
+	"sri lanka": "Sri Lanka", "nepal": "Nepal", "vietnam": "Vietnam", "south africa": "South Africa",
+}
+
+var usStates = map[string]bool{}
+
+func init() {
+	for _, s := range strings.Fields("AL AK AZ AR CA CO CT DE FL GA HI ID IL IN IA KS KY LA ME MD MA MI MN MS MO MT NE NV NH NJ NM NY NC ND OH OK OR PA RI SC SD TN TX UT VT VA WA WV WI WY DC") {
+		usStates[s] = true
+	}
+}
+
+// anywhere holds captions that name no place, such as Wellfound's "More" link
+var anywhere = map[string]bool{"more": true, "everywhere": true, "anywhere": true, "worldwide": true, "global": true}
+
+var (
+	policyParenPattern = regexp.MustCompile(`(?i)\((on-?site|remote|hybrid)\)`)
+	remoteInPattern    = regexp.MustCompile(`(?i)^remote\s*\(([^)]*)\)$`)
+	// "Hybrid - Bengaluru", "Hybrid work in Pune", "Remote in Austin, TX"
+	policyPrefixPattern = regexp.MustCompile(`(?i)^(on-?site|remote|hybrid)(?:\s+work)?\s*(?:-|–|:|\bin\b)\s*`)
+	placeSeparator      = regexp.MustCompile(`\s*(?:•|·|\||;|/| or | and )\s*`)
+)
+
+// ParseLocation reads a location caption such as "Bengaluru, Karnataka,
+// India (Hybrid)", Wellfound's "Onsite or remote • Bangalore Urban • Remote
+// (India)" or "Remote only". Mode is an optional separate remote policy field
+// like Cuvette's "Work from home". It returns nil when nothing is recognized.
+func ParseLocation(caption, mode string) *Workplace {
+	w := &Workplace{Policy: ParsePolicy(mode)}
+
+	caption = strings.TrimSpace(caption)
+	if m := policyParenPattern.FindStringSubmatch(caption); m != nil {
+		if w.Policy == "" {
+			w.Policy = ParsePolicy(m[1])
+		}
+		caption = strings.TrimSpace(policyParenPattern.ReplaceAllString(caption, ""))
+	}
+
+	// A caption without separators is a single "City, Region, Country" place
+	parts := []string{caption}
+	if placeSeparator.MatchString(caption) {
+		parts = placeSeparator.Split(caption, -1)
+	}
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if m := remoteInPattern.FindStringSubmatch(part); m != nil {
+			w.setPolicy(PolicyRemote)
+			part = strings.TrimSpace(m[1])
+		} else if m := policyPrefixPattern.FindStringSubmatch(part); m != nil {
+			w.setPolicy(ParsePolicy(m[1]))
+			part = strings.TrimSpace(part[len(m[0]):])
+		} else if policy := ParsePolicy(part); policy != "" {
+			w.setPolicy(policy)
+			continue
+		}
+		if part == "" || anywhere[strings.ToLower(part)] {
+			continue
+		}
+		for _, place := range cityList(part) {
+			if p, ok := parsePlace(place); ok {
+				w.addPlace(p)
+			}
+		}
+	}
+
+	if w.Policy == "" && len(w.Places) == 0 {
+		return nil
+	}
+	return w
+}
+
+// ParsePolicy recognizes how a platform says remote, hybrid or on-site.
+// "Onsite or remote" counts as remote since working remotely is allowed.
+func ParsePolicy(text string) string {
+	t := strings.ToLower(strings.TrimSpace(text))
+	switch {
+	case t == "":
+		return ""
+	case strings.Contains(t, "hybrid"):
+		return PolicyHybrid
+	case strings.Contains(t, "remote"), strings.Contains(t, "work from home"), t == "wfh":
+		return PolicyRemote
+	case strings.Contains(t, "on-site"), strings.Contains(t, "onsite"), strings.Contains(t, "on site"),
+		strings.Contains(t, "in office"), strings.Contains(t, "in-office"), strings.Contains(t, "work from office"), t == "wfo":
+		return PolicyOnsite
+	}
+	return ""
+}
+
+// ResolveCity returns the canonical city and metro for a name or alias,
+// e.g. "Gurgaon" gives "Gurugram" in "Delhi NCR".
+func ResolveCity(name string) (city, metro string, ok bool) {
+	c, ok := cityAliases[strings.ToLower(strings.TrimSpace(name))]
+	return c.city, c.metro, ok
+}
+
+// cityList splits "Kochi, Bengaluru, Gurugram" into its cities. Text with a
+// region or country in it, like "Pune, Maharashtra", is one place and is
+// returned whole.
+func cityList(text string) []string {
+	fields := strings.Split(text, ",")
+	if len(fields) < 2 {
+		return []string{text}
+	}
+	known := false
+	for i, field := range fields {
+		field = strings.TrimSpace(field)
+		key := strings.ToLower(field)
+		if _, ok := countries[key]; ok {
+			return []string{text}
+		}
+		if _, ok := regions[key]; ok && cityAliases[key].city == "" {
+			return []string{text}
+		}
+		if usStates[field] {
+			return []string{text}
+		}
+		if cityAliases[key].city != "" {
+			known = true
+		}
+		fields[i] = field
+	}
+	if !known {
+		return []string{text}
+	}
+	return fields
+}
+
+// parsePlace reads "City, Region, Country" with any of the parts missing.
+func parsePlace(text string) (Place, bool) {
+	var p Place
+	var rest []string
+	for _, field := range strings.Split(text, ",") {
+		field = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(field), " district"))
+		if field != "" {
+			rest = append(rest, field)
+		}
+	}
+
+	// Country and region come last; whatever precedes them is the city
+	if n := len(rest); n > 0 {
+		if country, ok := countries[strings.ToLower(rest[n-1])]; ok {
+			p.Country = country
+			rest = rest[:n-1]
+		}
+	}
+	if n := len(rest); n > 0 {
+		last := rest[n-1]
+		if region, ok := regions[strings.ToLower(last)]; ok && (n > 1 || cityAliases[strings.ToLower(last)].city == "") {
+			p.Region = region[0]
+			if p.Country == "" {
+				p.Country = region[1]
+			}
+			rest = rest[:n-1]
+		} else if usStates[last] && n > 1 {
+			p.Region = last
+			if p.Country == "" {
+				p.Country = "United States"
+			}
+			rest = rest[:n-1]
+		}
+	}
+	if len(rest) > 0 {
+		name := strings.Join(rest, ", ")
+		if c, ok := cityAliases[strings.ToLower(name)]; ok {
+			p.City, p.Metro = c.city, c.metro
+			if p.Region == "" {
+				p.Region = c.region
+			}
+			if p.Country == "" {
+				p.Country = c.country
+			}
+		} else {
+			p.City = name
+		}
+	}
+
+	return p, p != Place{}
+}
+
+func (w *Workplace) setPolicy(policy string) {
+	// "Onsite or remote" lists both; remote is what matters for filtering
+	if w.Policy == "" || policy == PolicyRemote {
+		w.Policy = policy
+	}
+}
+
+func (w *Workplace) addPlace(p Place) {
+	for _, seen := range w.Places {
+		if seen == p {
+			return
+		}
+	}
+	w.Places = append(w.Places, p)
+	if w.City == "" && p.City != "" {
+		w.City = p.City
+	}
+	if w.Region == "" && p.Region != "" {
+		w.Region = p.Region
+	}
+	if w.Country == "" && p.Country != "" {
+		w.Country = p.Country
+	}
+}
+
+// Cities returns the canonical cities and metros of every place, the values
+// a "jobs in my city" filter compares against.
+func (w *Workplace) Cities() []string {
+	var cities []string
+	seen := make(map[string]bool)
+	for _, p := range w.Places {
+		for _, c := range []string{p.City, p.Metro} {
+			if c != "" && !seen[c] {
+				seen[c] = true
+				cities = append(cities, c)
+			}
+		}
+	}
+	return cities
+}
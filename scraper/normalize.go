@@ -0,0 +1,62 @@
+package scraper
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+var internPattern = regexp.MustCompile(`(?i)\bintern(ship)?\b`)
+
+// Normalize fills the fields derived from what a platform printed. Every
+// scrape runs its jobs through it, whatever the platform. Relative dates are
+// read as of the time it runs.
+func Normalize(jobs []Job) {
+	now := time.Now().UTC()
+	for i := range jobs {
+		job := &jobs[i]
+		if job.Compensation == nil {
+			job.Compensation = ParseCompensation(job.Salary)
+		}
+		if job.Compensation != nil && isInternship(job) {
+			job.Compensation.Stipend = true
+		}
+		if job.Workplace == nil {
+			job.Workplace = ParseLocation(jobLocation(job), jobMode(job))
+		}
+		if job.PostedAt == nil {
+			if t, ok := ResolvePosted(job.Extras["posted_ago"], now); ok {
+				job.PostedAt = &t
+			}
+		}
+		if job.ApplyBy == nil {
+			if t, ok := ResolveDeadline(job.Extras["apply_by"], now); ok {
+				job.ApplyBy = &t
+			}
+		}
+	}
+}
+
+// isInternship reports whether pay for the job is a stipend rather than a salary.
+func isInternship(job *Job) bool {
+	return strings.EqualFold(job.Extras["type"], "internship") ||
+		strings.EqualFold(job.Extras["employment_type"], "internship") ||
+		internPattern.MatchString(job.Role)
+}
+
+// jobLocation is the location caption of a job, falling back to the office
+// Cuvette lists separately.
+func jobLocation(job *Job) string {
+	if job.Location != "" {
+		return job.Location
+	}
+	return job.Extras["office_location"]
+}
+
+// jobMode is the remote policy a platform gives apart from the location.
+func jobMode(job *Job) string {
+	if mode := job.Extras["workplace_type"]; mode != "" {
+		return mode
+	}
+	return job.Extras["mode"]
+}
@@ -0,0 +1,162 @@
+package scraper
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Pay periods of a Compensation
+const (
+	PeriodHour  = "hour"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+	PeriodYear  = "year"
+)
+
+// Compensation is a salary string broken into comparable parts. Amounts are
+// in whole currency units per Period, e.g. 3000000 INR a year for "₹30L".
+type Compensation struct {
+	Min       float64 `json:"min"`
+	Max       float64 `json:"max"`
+	Currency  string  `json:"currency"`             // ISO 4217 code, empty when unknown
+	Period    string  `json:"period"`               // hour, week, month or year
+	EquityMin float64 `json:"equity_min,omitempty"` // percent
+	EquityMax float64 `json:"equity_max,omitempty"` // percent
+	Stipend   bool    `json:"stipend"`
+}
+
+var (
+	percentPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*%`)
+	amountPattern  = regexp.MustCompile(`(?i)(\d[\d,]*(?:\.\d+)?)\s*(lpa|lakhs?|lacs?|crores?|cr|mn|[klm])?(?:\b|$)`)
+
+	// Checked in order; the first currency found wins
+	currencyMarkers = []struct{ marker, code string }{
+		{"CA$", "CAD"}, {"C$", "CAD"}, {"A$", "AUD"}, {"AU$", "AUD"}, {"S$", "SGD"},
+		{"₹", "INR"}, {"RS.", "INR"}, {"RS ", "INR"}, {"INR", "INR"},
+		{"$", "USD"}, {"USD", "USD"}, {"€", "EUR"}, {"EUR", "EUR"}, {"£", "GBP"}, {"GBP", "GBP"},
+		{"CAD", "CAD"}, {"AUD", "AUD"}, {"SGD", "SGD"},
+	}
+
+	periodPatterns = []struct {
+		pattern *regexp.Regexp
+		period  string
+	}{
+		{regexp.MustCompile(`(?i)(/\s*(hr|hour)\b|per hour|an hour|hourly)`), PeriodHour},
+		{regexp.MustCompile(`(?i)(/\s*(wk|week)\b|per week|a week|weekly)`), PeriodWeek},
+		{regexp.MustCompile(`(?i)(/\s*(mo|month)\b|per month|a month|monthly|\bp\.?m\.?$)`), PeriodMonth},
+		{regexp.MustCompile(`(?i)(/\s*(yr|year|annum)\b|per (year|annum)|a year|annually|yearly|\bp\.?a\.?\b|\blpa\b|\bctc\b)`), PeriodYear},
+	}
+)
+
+// ParseCompensation reads salary text as the platforms print it, e.g.
+// "₹4L – ₹6L", "$120k – $150k • 0.5% equity", "₹ 40,000 /month",
+// "₹4 - 5 LPA" or "$50/hr - $60/hr". It returns nil when the text holds
+// no amount ("Unpaid", "Competitive").
+func ParseCompensation(text string) *Compensation {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	comp := &Compensation{}
+
+	// Equity is the only part given in percent
+	percents := percentPattern.FindAllStringSubmatch(text, -1)
+	if len(percents) > 0 {
+		comp.EquityMin, _ = strconv.ParseFloat(percents[0][1], 64)
+		comp.EquityMax, _ = strconv.ParseFloat(percents[len(percents)-1][1], 64)
+	}
+	pay := percentPattern.ReplaceAllString(text, "")
+	comp.Stipend = strings.Contains(strings.ToLower(text), "stipend")
+
+	type amount struct {
+		value float64
+		unit  float64
+	}
+	var amounts []amount
+	indian := false
+	for _, m := range amountPattern.FindAllStringSubmatch(pay, -1) {
+		value, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64)
+		if err != nil {
+			continue
+		}
+		unit := 0.0
+		switch strings.ToLower(m[2]) {
+		case "k":
+			unit = 1e3
+		case "m", "mn":
+			unit = 1e6
+		case "l", "lpa", "lakh", "lakhs", "lac", "lacs":
+			unit, indian = 1e5, true
+		case "cr", "crore", "crores":
+			unit, indian = 1e7, true
+		}
+		amounts = append(amounts, amount{value, unit})
+		if len(amounts) == 2 {
+			break
+		}
+	}
+	if len(amounts) == 0 {
+		if comp.EquityMax > 0 {
+			return comp
+		}
+		return nil
+	}
+
+	// "4 - 5 LPA" and "$120-150k" put the unit on the upper bound only
+	if len(amounts) == 2 && amounts[0].unit == 0 && amounts[1].unit != 0 && amounts[0].value < amounts[1].value {
+		amounts[0].unit = amounts[1].unit
+	}
+	for i := range amounts {
+		if amounts[i].unit != 0 {
+			amounts[i].value *= amounts[i].unit
+		}
+	}
+
+	comp.Min = amounts[0].value
+	comp.Max = amounts[len(amounts)-1].value
+	lower := strings.ToLower(pay)
+	if len(amounts) == 1 && strings.Contains(lower, "up to") {
+		comp.Min = 0
+	}
+	if comp.Min > comp.Max && comp.Max > 0 {
+		comp.Min, comp.Max = comp.Max, comp.Min
+	}
+
+	upper := strings.ToUpper(pay)
+	for _, c := range currencyMarkers {
+		if strings.Contains(upper, c.marker) {
+			comp.Currency = c.code
+			break
+		}
+	}
+	if comp.Currency == "" && indian {
+		comp.Currency = "INR"
+	}
+
+	for _, p := range periodPatterns {
+		if p.pattern.MatchString(pay) {
+			comp.Period = p.period
+			break
+		}
+	}
+	if comp.Period == "" {
+		comp.Period = guessPeriod(comp, amounts[len(amounts)-1].unit != 0)
+	}
+	return comp
+}
+
+// guessPeriod picks the likeliest period for an amount printed without one.
+// Shorthand such as "30L" or "120k" is how yearly pay is written; small plain
+// numbers are hourly rates, and plain rupee amounts below a lakh are monthly.
+func guessPeriod(comp *Compensation, shorthand bool) string {
+	switch {
+	case shorthand:
+		return PeriodYear
+	case comp.Max < 1000:
+		return PeriodHour
+	case comp.Currency == "INR" && comp.Max < 1e5:
+		return PeriodMonth
+	}
+	return PeriodYear
+}
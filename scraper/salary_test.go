@@ -0,0 +1,51 @@
+package scraper
+
+import "testing"
+
+func TestParseCompensation(t *testing.T) {
+	tests := []struct {
+		text string
+		want *Compensation
+	}{
+		// Wellfound
+		{"$120k – $150k • 0.5% equity", &Compensation{Min: 120000, Max: 150000, Currency: "USD", Period: PeriodYear, EquityMin: 0.5, EquityMax: 0.5}},
+		{"₹4L – ₹6L", &Compensation{Min: 400000, Max: 600000, Currency: "INR", Period: PeriodYear}},
+		{"0.5% – 1.0%", &Compensation{EquityMin: 0.5, EquityMax: 1}},
+		{"$120-150k", &Compensation{Min: 120000, Max: 150000, Currency: "USD", Period: PeriodYear}},
+		{"$50/hr - $60/hr", &Compensation{Min: 50, Max: 60, Currency: "USD", Period: PeriodHour}},
+
+		// Cuvette and Internshala
+		{"40,000/month", &Compensation{Min: 40000, Max: 40000, Period: PeriodMonth}},
+		{"₹ 40,000 /month", &Compensation{Min: 40000, Max: 40000, Currency: "INR", Period: PeriodMonth}},
+		{"Stipend ₹10,000", &Compensation{Min: 10000, Max: 10000, Currency: "INR", Period: PeriodMonth, Stipend: true}},
+
+		// LinkedIn and Naukri
+		{"₹4 - 5 LPA", &Compensation{Min: 400000, Max: 500000, Currency: "INR", Period: PeriodYear}},
+		{"₹1.2 Cr", &Compensation{Min: 1.2e7, Max: 1.2e7, Currency: "INR", Period: PeriodYear}},
+		{"£500 a week", &Compensation{Min: 500, Max: 500, Currency: "GBP", Period: PeriodWeek}},
+
+		// "up to" leaves the lower bound open
+		{"Up to ₹6L", &Compensation{Max: 600000, Currency: "INR", Period: PeriodYear}},
+		{"up to $150k", &Compensation{Max: 150000, Currency: "USD", Period: PeriodYear}},
+
+		// guessPeriod: shorthand is yearly, small plain numbers hourly,
+		// plain rupees below a lakh monthly, anything else yearly
+		{"$45", &Compensation{Min: 45, Max: 45, Currency: "USD", Period: PeriodHour}},
+		{"₹ 15,000", &Compensation{Min: 15000, Max: 15000, Currency: "INR", Period: PeriodMonth}},
+		{"₹3,00,000", &Compensation{Min: 300000, Max: 300000, Currency: "INR", Period: PeriodYear}},
+		{"€60,000", &Compensation{Min: 60000, Max: 60000, Currency: "EUR", Period: PeriodYear}},
+
+		// no amount at all
+		{"Unpaid", nil},
+		{"Competitive", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got := ParseCompensation(tt.text)
+		switch {
+		case got == nil && tt.want == nil:
+		case got == nil || tt.want == nil || *got != *tt.want:
+			t.Errorf("ParseCompensation(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package scraper
+
+import (
+	"io"
+	"sort"
+	"sync"
+)
+
+// Scraper turns a saved page of one platform into jobs.
+type Scraper interface {
+	// Platform is the name used in /api/scrape/:platform, e.g. "linkedin".
+	Platform() string
+	Scrape(r io.Reader) ([]Job, error)
+}
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[string]Scraper)
+)
+
+// Register makes a scraper available under its platform name. Platform
+// packages call it from init; registering a name twice panics.
+func Register(s Scraper) {
+	mu.Lock()
+	defer mu.Unlock()
+	name := s.Platform()
+	if _, dup := registry[name]; dup {
+		panic("scraper: Register called twice for platform " + name)
+	}
+	registry[name] = s
+}
+
+// Get returns the scraper registered for platform.
+func Get(platform string) (Scraper, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	s, ok := registry[platform]
+	return s, ok
+}
+
+// Platforms returns the registered platform names in sorted order.
+func Platforms() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,119 @@
+// Package scrapertest checks a platform scraper against a saved page, after
+// the jobs went through scraper.Normalize like every stored scrape does.
+package scrapertest
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"aiapply/scraper"
+)
+
+// Want is what one listing of a saved page must come out as.
+type Want struct {
+	ID       string
+	Role     string
+	Company  string
+	Location string
+	Salary   string
+	URL      string
+
+	// Extras holds the extras to check; others the job has are ignored
+	Extras map[string]string
+	// Age is how long before the scrape the job was posted; PostedAt is the
+	// date instead for pages that print one; Undated means the page gives no
+	// posting date
+	Age      time.Duration
+	PostedAt time.Time
+	Undated  bool
+
+	Compensation *scraper.Compensation // nil when no pay is given
+	Policy       string                // remote policy
+	City         string
+}
+
+// CheckFixture scrapes and normalizes the saved page in file with s and
+// compares the jobs, in page order, with want.
+func CheckFixture(t *testing.T, s scraper.Scraper, file string, want []Want) {
+	t.Helper()
+	jobs := scrape(t, s, file)
+	now := time.Now()
+	scraper.Normalize(jobs)
+
+	if len(jobs) != len(want) {
+		t.Fatalf("%s: got %d jobs, want %d", file, len(jobs), len(want))
+	}
+	for i, w := range want {
+		job := jobs[i]
+		if job.Platform != s.Platform() {
+			t.Errorf("job %d: platform %q, want %q", i, job.Platform, s.Platform())
+		}
+		got := [6]string{job.ID, job.Role, job.Company, job.Location, job.Salary, job.URL}
+		if exp := [6]string{w.ID, w.Role, w.Company, w.Location, w.Salary, w.URL}; got != exp {
+			t.Errorf("job %d: ID, role, company, location, salary, URL = %q, want %q", i, got, exp)
+		}
+		for key, value := range w.Extras {
+			if job.Extras[key] != value {
+				t.Errorf("job %s: extra %s = %q, want %q", w.ID, key, job.Extras[key], value)
+			}
+		}
+
+		switch {
+		case w.Undated && job.PostedAt != nil:
+			t.Errorf("job %s: posted at %v, want no date", w.ID, job.PostedAt)
+		case w.Undated:
+		case job.PostedAt == nil:
+			t.Errorf("job %s: no posting date", w.ID)
+		case !w.PostedAt.IsZero():
+			if !job.PostedAt.Equal(w.PostedAt) {
+				t.Errorf("job %s: posted at %v, want %v", w.ID, job.PostedAt, w.PostedAt)
+			}
+		default:
+			if age := now.Sub(*job.PostedAt); age < w.Age-time.Minute || age > w.Age+time.Minute {
+				t.Errorf("job %s: posted %v ago, want %v", w.ID, age.Round(time.Minute), w.Age)
+			}
+		}
+
+		if !reflect.DeepEqual(job.Compensation, w.Compensation) {
+			t.Errorf("job %s: compensation %+v, want %+v", w.ID, job.Compensation, w.Compensation)
+		}
+		var policy, city string
+		if job.Workplace != nil {
+			policy, city = job.Workplace.Policy, job.Workplace.City
+		}
+		if policy != w.Policy || city != w.City {
+			t.Errorf("job %s: workplace %q in %q, want %q in %q", w.ID, policy, city, w.Policy, w.City)
+		}
+	}
+}
+
+// CheckCardCount compares the cards counted on the saved page in file with n.
+func CheckCardCount(t *testing.T, counter scraper.CardCounter, file string, n int) {
+	t.Helper()
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if got, err := counter.CountCards(f); err != nil || got != n {
+		t.Errorf("CountCards(%s) = %d, %v; want %d", file, got, err, n)
+	}
+}
+
+func scrape(t *testing.T, s scraper.Scraper, file string) []scraper.Job {
+	t.Helper()
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	jobs, err := s.Scrape(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jobs
+}
@@ -0,0 +1,201 @@
+package scraper
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed selectors.yaml
+var defaultSelectors []byte
+
+// SelectorConfig is the parsed selector file: for every platform, a chain
+// of CSS selectors per field.
+type SelectorConfig struct {
+	Version   int                  `yaml:"version" json:"version"`
+	Platforms map[string]Selectors `yaml:"platforms" json:"platforms"`
+}
+
+// Selectors maps a field to the selectors tried for it, in order.
+type Selectors map[string][]string
+
+// SelectorSource describes the selectors in use.
+type SelectorSource struct {
+	Version  int       `json:"version"`
+	Path     string    `json:"path"` // empty when only the built-in selectors are used
+	LoadedAt time.Time `json:"loaded_at"`
+}
+
+var (
+	selectorMu     sync.RWMutex
+	selectorConfig *SelectorConfig
+	selectorSource SelectorSource
+	builtin        *SelectorConfig
+)
+
+func init() {
+	config, err := ParseSelectors(defaultSelectors)
+	if err != nil {
+		panic("scraper: built-in selectors: " + err.Error())
+	}
+	builtin = config
+	selectorConfig = config
+	selectorSource = SelectorSource{Version: config.Version, LoadedAt: time.Now()}
+}
+
+// ParseSelectors reads a selector file and checks that it has a version and
+// that every selector compiles.
+func ParseSelectors(data []byte) (*SelectorConfig, error) {
+	var config SelectorConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("could not parse selectors: %w", err)
+	}
+	if config.Version <= 0 {
+		return nil, fmt.Errorf("selectors have no version")
+	}
+	for platform, fields := range config.Platforms {
+		for field, chain := range fields {
+			if len(chain) == 0 {
+				return nil, fmt.Errorf("%s.%s has no selectors", platform, field)
+			}
+			for _, sel := range chain {
+				if _, err := cascadia.ParseGroup(sel); err != nil {
+					return nil, fmt.Errorf("%s.%s: invalid selector %q: %w", platform, field, sel, err)
+				}
+			}
+		}
+	}
+	return &config, nil
+}
+
+// LoadSelectorFile replaces the selectors in use with the built-in ones
+// overridden by the file at path, field by field. On error the selectors in
+// use are kept.
+func LoadSelectorFile(path string) (SelectorSource, error) {
+	_, current := CurrentSelectors()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return current, fmt.Errorf("could not read selectors: %w", err)
+	}
+	override, err := ParseSelectors(data)
+	if err != nil {
+		return current, err
+	}
+
+	merged := &SelectorConfig{Version: override.Version, Platforms: make(map[string]Selectors)}
+	for platform, fields := range builtin.Platforms {
+		merged.Platforms[platform] = make(Selectors, len(fields))
+		for field, chain := range fields {
+			merged.Platforms[platform][field] = chain
+		}
+	}
+	for platform, fields := range override.Platforms {
+		if merged.Platforms[platform] == nil {
+			merged.Platforms[platform] = make(Selectors, len(fields))
+		}
+		for field, chain := range fields {
+			merged.Platforms[platform][field] = chain
+		}
+	}
+
+	selectorMu.Lock()
+	defer selectorMu.Unlock()
+	selectorConfig = merged
+	selectorSource = SelectorSource{Version: merged.Version, Path: path, LoadedAt: time.Now()}
+	return selectorSource, nil
+}
+
+// WatchSelectors loads the selector file at path and reloads it whenever
+// its modification time changes, checking every interval until ctx is done.
+// A file that fails to load is logged and the previous selectors stay.
+func WatchSelectors(ctx context.Context, path string, interval time.Duration) {
+	var modTime time.Time
+	check := func() {
+		info, err := os.Stat(path)
+		if err != nil {
+			log.Printf("selectors: %v", err)
+			return
+		}
+		if info.ModTime().Equal(modTime) {
+			return
+		}
+		modTime = info.ModTime()
+		source, err := LoadSelectorFile(path)
+		if err != nil {
+			log.Printf("selectors: keeping version %d: %v", source.Version, err)
+			return
+		}
+		log.Printf("selectors: loaded version %d from %s", source.Version, path)
+	}
+
+	check()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				check()
+			}
+		}
+	}()
+}
+
+// CurrentSelectors returns the selectors in use and where they came from.
+func CurrentSelectors() (*SelectorConfig, SelectorSource) {
+	selectorMu.RLock()
+	defer selectorMu.RUnlock()
+	return selectorConfig, selectorSource
+}
+
+// SelectorsFor returns the selectors in use for platform. Parsers should
+// call it once per page so a reload never mixes versions within one page.
+func SelectorsFor(platform string) Selectors {
+	selectorMu.RLock()
+	defer selectorMu.RUnlock()
+	return selectorConfig.Platforms[platform]
+}
+
+// Find returns what the first selector of field's chain matches within sel.
+func (s Selectors) Find(sel *goquery.Selection, field string) *goquery.Selection {
+	for _, selector := range s[field] {
+		if found := sel.Find(selector); found.Length() > 0 {
+			return found
+		}
+	}
+	return sel.Slice(0, 0)
+}
+
+// Text returns the whitespace-collapsed text of the first element found
+// for field, moving down the chain while the match has no text.
+func (s Selectors) Text(sel *goquery.Selection, field string) string {
+	for _, selector := range s[field] {
+		if text := strings.Join(strings.Fields(sel.Find(selector).First().Text()), " "); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// Attr returns attr of the first element found for field.
+func (s Selectors) Attr(sel *goquery.Selection, field, attr string) string {
+	value, _ := s.Find(sel, field).First().Attr(attr)
+	return value
+}
+
+// Group joins field's chain into one selector matching any of them, for
+// callers such as the browser that cannot walk a chain.
+func (s Selectors) Group(field string) string {
+	return strings.Join(s[field], ", ")
+}
@@ -0,0 +1,68 @@
+package scraper
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var blockEnd = regexp.MustCompile(`(?i)</(p|li|div|h[1-6]|tr)>|<br\s*/?>`)
+
+// PlainText strips the HTML job descriptions usually carry, escaped or not,
+// keeping paragraphs and list items on lines of their own.
+func PlainText(s string) string {
+	if s == "" {
+		return ""
+	}
+	s = blockEnd.ReplaceAllString(html.UnescapeString(s), "$0\n")
+	if doc, err := goquery.NewDocumentFromReader(strings.NewReader(s)); err == nil {
+		s = doc.Text()
+	}
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FormatAmount prints an amount with thousands separators.
+func FormatAmount(v float64) string {
+	digits := strconv.FormatFloat(v, 'f', -1, 64)
+	whole, fraction, _ := strings.Cut(digits, ".")
+	var b strings.Builder
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	if fraction != "" {
+		b.WriteString("." + fraction)
+	}
+	return b.String()
+}
+
+var (
+	nonAlphanumeric = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+	// legal suffixes companies drop on one site and keep on another
+	companySuffixes = map[string]bool{
+		"inc": true, "incorporated": true, "llc": true, "llp": true, "ltd": true, "limited": true,
+		"pvt": true, "private": true, "corp": true, "corporation": true, "co": true, "company": true,
+		"gmbh": true, "plc": true, "pte": true, "sa": true, "bv": true,
+	}
+)
+
+// CompanyKey reduces a company name to what stays the same across sites, so
+// "Stripe, Inc." and "stripe" compare equal.
+func CompanyKey(name string) string {
+	words := strings.Fields(nonAlphanumeric.ReplaceAllString(strings.ToLower(name), " "))
+	for len(words) > 1 && companySuffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
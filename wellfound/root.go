@@ -3,6 +3,11 @@ package wellfound
 import (
 	"fmt"
 	"io"
+	"log"
+	"strings"
+	"time"
+
+	"aiapply/scraper"
 
 	"github.com/PuerkitoBio/goquery"
 )
@@ -16,28 +21,85 @@ type JobDetail struct {
 	CompanyPhotoURL string
 	Location        string
 	Salary          string
+	JobURL          string
+	PostedAgo       string
+
+	// Only the embedded page state has these
+	CompanySize     string   // e.g. "11-50"
+	Stage           string   // e.g. "Series A"
+	Markets         []string // e.g. "Fintech", "SaaS"
+	Remote          string   // e.g. "Remote", "Onsite or remote"
+	VisaSponsorship string   // "Yes" or "No", empty when not stated
+	JobType         string   // e.g. "Full-time", "Internship"
+	Equity          string   // e.g. "0.5% – 1.0%"
+	Experience      string   // e.g. "3+ years"
+	PostedAt        *time.Time
 }
 
 // ScrapeJobDetailsFromReader scrapes job listings from a Wellfound HTML file and returns detailed info.
+// The page state embedded by Next.js is read when it holds the listings, the cards otherwise.
 func ScrapeJobDetailsFromReader(reader io.Reader) ([]JobDetail, error) {
 	doc, err := goquery.NewDocumentFromReader(reader)
 	if err != nil {
 		return nil, fmt.Errorf("could not parse HTML: %w", err)
 	}
 
+	// pages without the state are read from the markup quietly; only a
+	// state that is there but cannot be read is worth a line in the log
+	details, err := ScrapeJobDetailsFromState(doc)
+	if err != nil {
+		log.Printf("wellfound: falling back to page markup: %v", err)
+	}
+	if len(details) > 0 {
+		return details, nil
+	}
+	return scrapeJobDetailsFromDOM(doc), nil
+}
+
+// scrapeJobDetailsFromDOM reads the job cards of each startup on the page.
+func scrapeJobDetailsFromDOM(doc *goquery.Document) []JobDetail {
 	var details []JobDetail
 
+	sel := scraper.SelectorsFor("wellfound")
+
 	// Find each job listing inside the listings container
-	doc.Find(`[data-test="StartupResult"]`).Each(func(i int, s *goquery.Selection) {
-		companyName := s.Find("h2").Text()
-		companyURL, _ := s.Find("a").Attr("href")
-		companyPhotoURL, _ := s.Find("img").Attr("src")
+	sel.Find(doc.Selection, "startup").Each(func(i int, s *goquery.Selection) {
+		companyName := sel.Text(s, "company")
+		companyURL := sel.Attr(s, "company_link", "href")
+		companyPhotoURL := sel.Attr(s, "company_logo", "src")
 
-		s.Find(`[data-testid="job-listing-list"] > div`).Each(func(j int, job *goquery.Selection) {
+		sel.Find(s, "card").Each(func(j int, job *goquery.Selection) {
 			id, _ := job.Attr("data-id")
-			role := job.Find("[class*='styles_title']").Text()
-			location := job.Find("[class*='styles_locations']").Text()
-			salary := job.Find("[class*='styles_compensation']").Text()
+			jobURL := sel.Attr(job, "job_link", "href")
+			if id == "" {
+				// /jobs/2833521-founding-engineer
+				slug := strings.TrimPrefix(jobURL, "/jobs/")
+				if i := strings.Index(slug, "-"); i > 0 {
+					id = slug[:i]
+				}
+			}
+			role := sel.Text(job, "role")
+			// each place is its own span, e.g. "Onsite or remote", "Bengaluru", "Remote (India)"
+			var places []string
+			sel.Find(job, "location").Each(func(_ int, l *goquery.Selection) {
+				if text := strings.TrimSpace(l.Text()); text != "" {
+					places = append(places, text)
+				}
+			})
+			location := strings.Join(places, " • ")
+			if location == "" {
+				location = sel.Text(job, "locations")
+			}
+			salary := sel.Text(job, "salary")
+
+			var postedAgo string
+			sel.Find(job, "tags").Each(func(_ int, tag *goquery.Selection) {
+				// the repost icon nested in the tag carries its own text
+				text := strings.TrimSpace(tag.Clone().Children().Remove().End().Text())
+				if strings.HasPrefix(text, "Posted ") {
+					postedAgo = strings.TrimPrefix(text, "Posted ")
+				}
+			})
 
 			details = append(details, JobDetail{
 				ID:              id,
@@ -47,9 +109,11 @@ func ScrapeJobDetailsFromReader(reader io.Reader) ([]JobDetail, error) {
 				CompanyPhotoURL: companyPhotoURL,
 				Location:        location,
 				Salary:          salary,
+				JobURL:          jobURL,
+				PostedAgo:       postedAgo,
 			})
 		})
 	})
 
-	return details, nil
+	return details
 }
@@ -0,0 +1,93 @@
+package wellfound
+
+import (
+	"io"
+	"strings"
+
+	"aiapply/scraper"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func init() {
+	scraper.Register(Scraper{})
+}
+
+// Scraper maps Wellfound search results into scraper jobs.
+type Scraper struct{}
+
+func (Scraper) Platform() string { return "wellfound" }
+
+func (Scraper) Fingerprint() scraper.Fingerprint {
+	return scraper.Fingerprint{
+		Hosts:     []string{"wellfound.com", "angel.co"},
+		SiteNames: []string{"Wellfound"},
+		Markers:   []string{"startup"},
+		Strings:   []string{"wellfound.com/", "JobListingSearchResult"},
+	}
+}
+
+// FetchHints scrolls; Wellfound loads more startups as the page nears its end.
+func (Scraper) FetchHints() scraper.FetchHints {
+	return scraper.FetchHints{Card: scraper.SelectorsFor("wellfound").Group("card")}
+}
+
+// CountCards counts the job cards of every startup on the page, or the
+// listings in the embedded page state when it has them.
+func (Scraper) CountCards(r io.Reader) (int, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return 0, err
+	}
+	if details, err := ScrapeJobDetailsFromState(doc); err == nil && len(details) > 0 {
+		return len(details), nil
+	}
+	sel := scraper.SelectorsFor("wellfound")
+	return sel.Find(sel.Find(doc.Selection, "startup"), "card").Length(), nil
+}
+
+func (Scraper) Scrape(r io.Reader) ([]scraper.Job, error) {
+	details, err := ScrapeJobDetailsFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]scraper.Job, 0, len(details))
+	for _, d := range details {
+		job := scraper.Job{
+			ID:       d.ID,
+			Platform: "wellfound",
+			Role:     strings.TrimSpace(d.Role),
+			Company:  strings.TrimSpace(d.CompanyName),
+			Location: strings.TrimSpace(d.Location),
+			Salary:   strings.TrimSpace(d.Salary),
+			PostedAt: d.PostedAt,
+		}
+		switch {
+		case d.JobURL != "":
+			job.URL = absoluteURL(d.JobURL)
+		case d.ID != "":
+			job.URL = "https://wellfound.com/jobs/" + d.ID
+		}
+		job.SetExtra("company_url", absoluteURL(d.CompanyURL))
+		job.SetExtra("company_photo_url", absoluteURL(d.CompanyPhotoURL))
+		job.SetExtra("posted_ago", d.PostedAgo)
+		job.SetExtra("company_size", d.CompanySize)
+		job.SetExtra("stage", d.Stage)
+		job.SetExtra("markets", strings.Join(d.Markets, ", "))
+		job.SetExtra("workplace_type", d.Remote)
+		job.SetExtra("visa_sponsorship", d.VisaSponsorship)
+		job.SetExtra("employment_type", d.JobType)
+		job.SetExtra("equity", d.Equity)
+		job.SetExtra("experience", d.Experience)
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func absoluteURL(href string) string {
+	if strings.HasPrefix(href, "/") {
+		return "https://wellfound.com" + href
+	}
+	return href
+}
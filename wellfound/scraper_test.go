@@ -0,0 +1,175 @@
+package wellfound
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"aiapply/scraper"
+	"aiapply/scraper/scrapertest"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// The listings of wellfound_state.html come from the Apollo cache, which
+// holds the company and listing details the cards leave out.
+func TestScrapeStateFixture(t *testing.T) {
+	posted := func(day int) time.Time { return time.Date(2024, time.July, day, 7, 0, 0, 0, time.UTC) }
+	salary := func(min, max float64) *scraper.Compensation {
+		return &scraper.Compensation{Min: min, Max: max, Currency: "INR", Period: "year"}
+	}
+	ledgerly := map[string]string{
+		"company_url":       "https://wellfound.com/company/ledgerly",
+		"company_photo_url": "https://photos.wellfound.com/startups/i/9184411-medium_jpg?1714032211",
+		"company_size":      "11-50",
+		"stage":             "Series A",
+		"markets":           "Fintech, SaaS",
+	}
+	extras := func(company map[string]string, listing map[string]string) map[string]string {
+		for k, v := range company {
+			listing[k] = v
+		}
+		return listing
+	}
+
+	senior := salary(3000000, 4500000)
+	senior.EquityMin, senior.EquityMax = 0.1, 0.25
+	intern := salary(50000, 60000)
+	intern.Stipend = true
+
+	scrapertest.CheckFixture(t, Scraper{}, "wellfound_state.html", []scrapertest.Want{
+		{
+			ID: "3012877", Role: "Senior Backend Engineer (Go)", Company: "Ledgerly", Location: "Bengaluru", Salary: "₹30L – ₹45L • 0.1% – 0.25%",
+			URL: "https://wellfound.com/jobs/3012877-senior-backend-engineer-go",
+			Extras: extras(ledgerly, map[string]string{
+				"workplace_type": "Onsite or remote", "visa_sponsorship": "No", "employment_type": "Full-time",
+				"equity": "0.1% – 0.25%", "experience": "5+ years",
+			}),
+			PostedAt: posted(15), Compensation: senior, Policy: "remote", City: "Bengaluru",
+		},
+		{
+			// no visa and no equity in the cache, so neither extra is set
+			ID: "3012901", Role: "Software Engineering Intern", Company: "Ledgerly", Location: "Bengaluru", Salary: "₹50K – ₹60K",
+			URL: "https://wellfound.com/jobs/3012901-software-engineering-intern",
+			Extras: extras(ledgerly, map[string]string{
+				"workplace_type": "Onsite", "visa_sponsorship": "", "employment_type": "Internship",
+				"equity": "", "experience": "0-1 years",
+			}),
+			PostedAt: posted(20), Compensation: intern, Policy: "onsite", City: "Bengaluru",
+		},
+		{
+			ID: "2998410", Role: "Golang Developer", Company: "Tracxn", Salary: "₹18L – ₹28L",
+			URL: "https://wellfound.com/jobs/2998410-golang-developer",
+			Extras: map[string]string{
+				"company_url":  "https://wellfound.com/company/tracxn_technologies_ltd",
+				"company_size": "501-1000", "stage": "IPO", "markets": "Data and Analytics",
+				"workplace_type": "Remote", "visa_sponsorship": "Yes", "employment_type": "Full-time", "experience": "2-4 years",
+			},
+			PostedAt: posted(6), Compensation: salary(1800000, 2800000), Policy: "remote",
+		},
+	})
+}
+
+func TestScrapeJobDetailsFromState(t *testing.T) {
+	details, err := ScrapeJobDetailsFromState(readFixture(t, "wellfound_state.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(details) != 3 {
+		t.Fatalf("got %d details, want 3", len(details))
+	}
+	d := details[0]
+	if strings.Join(d.Markets, "|") != "Fintech|SaaS" {
+		t.Errorf("markets = %q, want Fintech and SaaS", d.Markets)
+	}
+	if d.CompanyURL != "/company/ledgerly" || d.JobURL != "/jobs/3012877-senior-backend-engineer-go" {
+		t.Errorf("URLs = %q, %q; want the paths the cards link to", d.CompanyURL, d.JobURL)
+	}
+	if d.PostedAgo != "" {
+		t.Errorf("posted ago = %q, want none: the cache has the date itself", d.PostedAgo)
+	}
+}
+
+// wellfound.html is a search page saved without the cache, so its listings
+// come from the cards, without a word in the log.
+func TestScrapeMarkupFixture(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	if details, err := ScrapeJobDetailsFromState(readFixture(t, "wellfound.html")); details != nil || err != nil {
+		t.Fatalf("state of a page without a cache = %d details, %v; want none", len(details), err)
+	}
+
+	f, err := os.Open("wellfound.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	details, err := ScrapeJobDetailsFromReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(details) != 141 {
+		t.Fatalf("got %d details, want 141", len(details))
+	}
+	d := details[0]
+	got := [8]string{d.ID, d.Role, d.CompanyName, d.CompanyURL, d.Location, d.Salary, d.JobURL, d.PostedAgo}
+	want := [8]string{"2833521", "Founding Engineer", "Nexera", "/company/nexera-2", "In office", "₹30L – ₹50L • 0.5% – 5.0%", "/jobs/2833521-founding-engineer", "2 weeks ago"}
+	if got != want {
+		t.Errorf("first card = %q, want %q", got, want)
+	}
+	for _, d := range details {
+		if d.CompanySize != "" || d.Stage != "" || d.JobType != "" || d.PostedAt != nil {
+			t.Errorf("card %s has %+v, which only the state has", d.ID, d)
+			break
+		}
+	}
+	if logged.Len() > 0 {
+		t.Errorf("logged %q for a page without a cache", logged.String())
+	}
+}
+
+// A cache that is there but broken is logged, and the cards are read instead.
+func TestScrapeBrokenState(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	page := `<html><body><script id="__NEXT_DATA__" type="application/json">{"props":</script></body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ScrapeJobDetailsFromState(doc); err == nil {
+		t.Error("state of a broken cache: no error")
+	}
+	if _, err := ScrapeJobDetailsFromReader(strings.NewReader(page)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(logged.String(), "falling back to page markup") {
+		t.Errorf("logged %q, want the fallback", logged.String())
+	}
+}
+
+func TestCountCards(t *testing.T) {
+	scrapertest.CheckCardCount(t, Scraper{}, "wellfound.html", 141)
+	scrapertest.CheckCardCount(t, Scraper{}, "wellfound_state.html", 3)
+}
+
+func readFixture(t *testing.T, file string) *goquery.Document {
+	t.Helper()
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	doc, err := goquery.NewDocumentFromReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
@@ -0,0 +1,302 @@
+package wellfound
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// apolloState is the normalized Apollo cache Next.js embeds in a page:
+// entries keyed "Typename:id" that point at each other through refs.
+type apolloState struct {
+	entries map[string]map[string]any
+	order   []string // keys as written, which is the order of the page
+}
+
+// ScrapeJobDetailsFromState reads the listings from the Apollo cache in the
+// page's __NEXT_DATA__ script, which holds more than the cards show. It
+// returns no details when the page has no cache or no listings in it.
+func ScrapeJobDetailsFromState(doc *goquery.Document) ([]JobDetail, error) {
+	state, err := readState(doc)
+	if err != nil || state == nil {
+		return nil, err
+	}
+
+	// startups first, so that their listings come in page order
+	var details []JobDetail
+	seen := make(map[string]bool)
+	for _, key := range state.keys("StartupResult") {
+		startup := state.entries[key]
+		for _, ref := range state.list(startup, "highlightedJobListings", "jobListings") {
+			job := state.resolve(ref)
+			if job == nil || seen[str(job["id"])] {
+				continue
+			}
+			seen[str(job["id"])] = true
+			details = append(details, state.jobDetail(job, startup))
+		}
+	}
+	// listings whose startup is not in the cache, as on a single job page
+	for _, key := range state.keys("JobListingSearchResult", "JobListing") {
+		job := state.entries[key]
+		if seen[str(job["id"])] {
+			continue
+		}
+		seen[str(job["id"])] = true
+		details = append(details, state.jobDetail(job, state.resolve(job["startup"])))
+	}
+	return details, nil
+}
+
+func readState(doc *goquery.Document) (*apolloState, error) {
+	blob := strings.TrimSpace(doc.Find("script#__NEXT_DATA__").Text())
+	if blob == "" {
+		return nil, nil
+	}
+	var next struct {
+		Props struct {
+			PageProps struct {
+				ApolloState json.RawMessage `json:"apolloState"`
+			} `json:"pageProps"`
+		} `json:"props"`
+	}
+	if err := json.Unmarshal([]byte(blob), &next); err != nil {
+		return nil, fmt.Errorf("could not parse __NEXT_DATA__: %w", err)
+	}
+	raw := next.Props.PageProps.ApolloState
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	// newer pages wrap the cache in "data"
+	var wrapped struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &wrapped); err == nil && len(wrapped.Data) > 0 && string(wrapped.Data) != "null" {
+		raw = wrapped.Data
+	}
+
+	state := &apolloState{}
+	if err := json.Unmarshal(raw, &state.entries); err != nil {
+		return nil, fmt.Errorf("could not parse Apollo state: %w", err)
+	}
+	// a map loses the order of the keys, so read them again in sequence
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var skip json.RawMessage
+		if err := dec.Decode(&skip); err != nil {
+			return nil, err
+		}
+		state.order = append(state.order, key.(string))
+	}
+	return state, nil
+}
+
+// keys returns the cache keys of the given types in page order.
+func (s *apolloState) keys(types ...string) []string {
+	var keys []string
+	for _, key := range s.order {
+		typename, _, _ := strings.Cut(key, ":")
+		for _, t := range types {
+			if typename == t {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// resolve follows a reference, {"__ref": key} or the older
+// {"type": "id", "id": key}, to its entry. Inline objects are returned as
+// they are.
+func (s *apolloState) resolve(v any) map[string]any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil
+	}
+	if ref, ok := m["__ref"].(string); ok {
+		return s.entries[ref]
+	}
+	if m["type"] == "id" {
+		if ref, ok := m["id"].(string); ok {
+			return s.entries[ref]
+		}
+	}
+	return m
+}
+
+// list returns the entries of the first non-empty list field, resolving
+// references and unwrapping {"type": "json", "json": [...]} scalars.
+func (s *apolloState) list(entry map[string]any, fields ...string) []any {
+	for _, field := range fields {
+		v := entry[field]
+		if m, ok := v.(map[string]any); ok && m["type"] == "json" {
+			v = m["json"]
+		}
+		if items, ok := v.([]any); ok && len(items) > 0 {
+			return items
+		}
+	}
+	return nil
+}
+
+// names reads a list of strings or of named entries, such as markets.
+func (s *apolloState) names(entry map[string]any, fields ...string) []string {
+	var names []string
+	for _, item := range s.list(entry, fields...) {
+		if name, ok := item.(string); ok {
+			names = append(names, name)
+			continue
+		}
+		if e := s.resolve(item); e != nil {
+			for _, key := range []string{"displayName", "name", "label"} {
+				if name := str(e[key]); name != "" {
+					names = append(names, name)
+					break
+				}
+			}
+		}
+	}
+	return names
+}
+
+func (s *apolloState) jobDetail(job, startup map[string]any) JobDetail {
+	places := s.names(job, "locationNames")
+	d := JobDetail{
+		ID:         str(job["id"]),
+		Role:       str(job["title"]),
+		Location:   strings.Join(places, " • "),
+		Salary:     str(job["compensation"]),
+		JobType:    jobType(str(job["jobType"])),
+		Remote:     remotePolicy(job, len(places) > 0),
+		Experience: experience(job),
+		PostedAt:   unixTime(job["liveStartAt"]),
+	}
+	if slug := str(job["slug"]); d.ID != "" {
+		d.JobURL = "/jobs/" + d.ID
+		if slug != "" {
+			d.JobURL += "-" + slug
+		}
+	}
+	if _, equity, ok := strings.Cut(d.Salary, "•"); ok {
+		d.Equity = strings.TrimSpace(equity)
+	} else {
+		d.Equity = str(job["equity"])
+	}
+	if visa, ok := job["visaSponsorship"].(bool); ok {
+		d.VisaSponsorship = "No"
+		if visa {
+			d.VisaSponsorship = "Yes"
+		}
+	}
+
+	if startup != nil {
+		d.CompanyName = str(startup["name"])
+		if slug := str(startup["slug"]); slug != "" {
+			d.CompanyURL = "/company/" + slug
+		}
+		d.CompanyPhotoURL = str(startup["logoUrl"])
+		d.CompanySize = companySize(str(startup["companySize"]))
+		d.Stage = stage(str(startup["stage"]))
+		d.Markets = s.names(startup, "markets", "marketTags")
+	}
+	return d
+}
+
+// jobType turns "full-time" or "FULL_TIME" into "Full-time".
+func jobType(t string) string {
+	t = strings.ToLower(strings.ReplaceAll(t, "_", "-"))
+	if t == "" {
+		return ""
+	}
+	return strings.ToUpper(t[:1]) + t[1:]
+}
+
+// remotePolicy reads the listing's remote settings as the captions the DOM
+// shows, e.g. "Remote" or "Onsite or remote".
+func remotePolicy(job map[string]any, hasPlaces bool) string {
+	if policy := str(job["remotePolicy"]); policy != "" {
+		return strings.ReplaceAll(strings.ToLower(policy), "_", " ")
+	}
+	remote, ok := job["remote"].(bool)
+	switch {
+	case !ok:
+		return ""
+	case remote && hasPlaces:
+		return "Onsite or remote"
+	case remote:
+		return "Remote"
+	}
+	return "Onsite"
+}
+
+func experience(job map[string]any) string {
+	min, hasMin := job["yearsExperienceMin"].(float64)
+	max, hasMax := job["yearsExperienceMax"].(float64)
+	switch {
+	case hasMin && hasMax && max > min:
+		return fmt.Sprintf("%g-%g years", min, max)
+	case hasMin:
+		return fmt.Sprintf("%g+ years", min)
+	case hasMax:
+		return fmt.Sprintf("up to %g years", max)
+	}
+	return ""
+}
+
+// stage turns "SERIES_A" into "Series A"; captions are kept as they are.
+func stage(s string) string {
+	if s != strings.ToUpper(s) {
+		return s
+	}
+	words := strings.Fields(strings.ToLower(strings.ReplaceAll(s, "_", " ")))
+	for i, w := range words {
+		if w == "ipo" {
+			words[i] = "IPO"
+			continue
+		}
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// companySize turns "SIZE_11_50" into "11-50" and "SIZE_5000_PLUS" into
+// "5000+".
+func companySize(size string) string {
+	size = strings.TrimPrefix(size, "SIZE_")
+	if rest, ok := strings.CutSuffix(size, "_PLUS"); ok {
+		return rest + "+"
+	}
+	return strings.ReplaceAll(size, "_", "-")
+}
+
+func unixTime(v any) *time.Time {
+	seconds, ok := v.(float64)
+	if !ok || seconds <= 0 {
+		return nil
+	}
+	t := time.Unix(int64(seconds), 0).UTC()
+	return &t
+}
+
+func str(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}